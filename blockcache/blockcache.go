@@ -0,0 +1,334 @@
+// Package blockcache implements the cache of decoded strips and tiles shared
+// by lazily decoded TIFF images.
+//
+// A Cache holds blocks of many images under a single byte budget and evicts
+// the least recently used blocks once the budget is exceeded. Each image
+// accesses the cache through a Handle, which scopes keys to one directory of
+// one file and keeps per-image statistics:
+//
+//	c := blockcache.New(512 << 20)
+//	file := blockcache.NewFileID()
+//	h := c.Handle(file, 0)
+//	h.Add(3, tile)
+//	tile, ok := h.Get(3)
+package blockcache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBudget is the byte budget of Default.
+const DefaultBudget = 256 << 20
+
+// Default is the cache used by images that are not given one explicitly.
+var Default = New(DefaultBudget)
+
+// errPanicked is returned to goroutines waiting for a load that panicked.
+var errPanicked = errors.New("blockcache: block load panicked")
+
+// FileID identifies an opened file within a cache.
+type FileID uint64
+
+// lastFileID is the most recently assigned FileID.
+var lastFileID atomic.Uint64
+
+// NewFileID returns a FileID that has not been returned before.
+func NewFileID() FileID {
+	return FileID(lastFileID.Add(1))
+}
+
+// Key identifies a decoded block: a strip row or tile of one directory (IFD)
+// of one file.
+type Key struct {
+	File  FileID
+	IFD   int
+	Block uint64
+}
+
+// Stats reports cache activity, either for a whole cache or for one image.
+type Stats struct {
+	Hits      uint64 // lookups that found the block
+	Misses    uint64 // lookups that did not
+	Evictions uint64 // blocks dropped to honor the budget
+	Blocks    int    // blocks currently cached
+	Bytes     int64  // bytes currently cached
+}
+
+// Cache is a least-recently-used block cache bounded by a byte budget.
+// It is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	budget   int64
+	lru      *list.List // of *item, most recently used first
+	items    map[Key]*list.Element
+	inflight map[Key]*call
+	stats    Stats
+}
+
+// call is a block load in progress, shared by all goroutines requesting the block.
+type call struct {
+	done chan struct{}
+	data []byte
+	err  error
+	gen  uint64 // generation of the handle when the load started
+}
+
+// item is a cached block together with the handle that added it.
+type item struct {
+	key   Key
+	data  []byte
+	owner *Handle
+}
+
+// New returns an empty cache holding at most budget bytes. A budget <= 0
+// disables caching, except that the most recently added block is kept.
+func New(budget int64) *Cache {
+	return &Cache{
+		budget:   budget,
+		lru:      list.New(),
+		items:    map[Key]*list.Element{},
+		inflight: map[Key]*call{},
+	}
+}
+
+// Budget returns the byte budget of c.
+func (c *Cache) Budget() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.budget
+}
+
+// SetBudget changes the byte budget of c, evicting blocks as needed.
+func (c *Cache) SetBudget(budget int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.budget = budget
+	c.evict()
+}
+
+// Stats returns the statistics of c across all images.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Handle returns the view of c used by the image stored in directory ifd of file.
+func (c *Cache) Handle(file FileID, ifd int) *Handle {
+	return &Handle{cache: c, file: file, ifd: ifd}
+}
+
+// get returns the block for key, marking it as recently used.
+func (c *Cache) get(key Key, h *Handle) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(key, h)
+}
+
+// lookup is get with c.mu held.
+func (c *Cache) lookup(key Key, h *Handle) ([]byte, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		h.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	h.stats.Hits++
+	c.lru.MoveToFront(e)
+	return e.Value.(*item).data, true
+}
+
+// add stores data for key on behalf of h and evicts blocks over the budget.
+// The most recently added block is never evicted.
+func (c *Cache) add(key Key, data []byte, h *Handle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(key, data, h)
+}
+
+// load returns the block for key, calling fn to produce it on a miss.
+// Concurrent loads of the same key wait for the first one and share its
+// result; loads of different keys run in parallel.
+//
+// A load that finishes after h was purged returns its block without caching
+// it, so blocks read while an image is closed do not outlive the image.
+//
+// A waiter stops waiting when its own ctx is done. If the shared load fails
+// because the context of the goroutine running it was cancelled, waiters
+// whose contexts are still live retry the load themselves.
+func (c *Cache) load(ctx context.Context, key Key, h *Handle, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if data, ok := c.lookup(key, h); ok {
+		c.mu.Unlock()
+		return data, nil
+	}
+	if cl, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-cl.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if isContextError(cl.err) && ctx.Err() == nil {
+			return c.load(ctx, key, h, fn)
+		}
+		return cl.data, cl.err
+	}
+	cl := &call{done: make(chan struct{}), gen: h.gen}
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if cl.err == nil && cl.gen == h.gen {
+			c.insert(key, cl.data, h)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	cl.err = errPanicked // reported to waiters if fn panics
+	cl.data, cl.err = fn(ctx)
+	return cl.data, cl.err
+}
+
+// isContextError reports whether err stems from a cancelled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// insert is add with c.mu held.
+func (c *Cache) insert(key Key, data []byte, h *Handle) {
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	e := c.lru.PushFront(&item{key: key, data: data, owner: h})
+	c.items[key] = e
+	if h.items == nil {
+		h.items = map[*list.Element]struct{}{}
+	}
+	h.items[e] = struct{}{}
+	c.account(h, 1, int64(len(data)))
+	c.evict()
+}
+
+// purge removes all blocks added through h and keeps loads in progress from
+// adding theirs. It only visits the blocks of h.
+func (c *Cache) purge(h *Handle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h.gen++
+	for e := range h.items {
+		c.remove(e)
+	}
+}
+
+// evict drops least recently used blocks until c fits its budget.
+func (c *Cache) evict() {
+	for c.stats.Bytes > c.budget && c.lru.Len() > 1 {
+		e := c.lru.Back()
+		e.Value.(*item).owner.stats.Evictions++
+		c.stats.Evictions++
+		c.remove(e)
+	}
+}
+
+// remove drops the block held by e.
+func (c *Cache) remove(e *list.Element) {
+	it := e.Value.(*item)
+	c.lru.Remove(e)
+	delete(c.items, it.key)
+	delete(it.owner.items, e)
+	c.account(it.owner, -1, -int64(len(it.data)))
+}
+
+// account updates the block and byte counts of c and h.
+func (c *Cache) account(h *Handle, blocks int, bytes int64) {
+	c.stats.Blocks += blocks
+	c.stats.Bytes += bytes
+	h.stats.Blocks += blocks
+	h.stats.Bytes += bytes
+}
+
+// Handle is the view of a Cache used by a single image. Its keys are block
+// numbers within the image. It is safe for concurrent use.
+type Handle struct {
+	cache *Cache
+	file  FileID
+	ifd   int
+	stats Stats  // guarded by cache.mu
+	gen   uint64 // incremented by Purge; guarded by cache.mu
+
+	// items holds the LRU elements of the blocks added through the handle,
+	// so that Purge does not scan the blocks of other images. Guarded by
+	// cache.mu.
+	items map[*list.Element]struct{}
+}
+
+// Get returns the cached block, if present.
+// The returned slice must not be modified.
+func (h *Handle) Get(block uint64) ([]byte, bool) {
+	return h.cache.get(h.key(block), h)
+}
+
+// Contains reports whether the block is cached, without counting a lookup
+// in the statistics or marking the block as recently used.
+func (h *Handle) Contains(block uint64) bool {
+	h.cache.mu.Lock()
+	defer h.cache.mu.Unlock()
+	_, ok := h.cache.items[h.key(block)]
+	return ok
+}
+
+// Add caches data as the given block. data must not be modified afterwards.
+func (h *Handle) Add(block uint64, data []byte) {
+	h.cache.add(h.key(block), data, h)
+}
+
+// Budget returns the byte budget of the cache, shared with other images.
+func (h *Handle) Budget() int64 {
+	return h.cache.Budget()
+}
+
+// File returns the file the image belongs to.
+func (h *Handle) File() FileID {
+	return h.file
+}
+
+// Load returns the cached block or, on a miss, calls load to read and
+// decode it and caches the result. Concurrent calls for the same block run
+// load once and share its result; errors are returned but not cached.
+func (h *Handle) Load(block uint64, load func() ([]byte, error)) ([]byte, error) {
+	return h.LoadContext(context.Background(), block, func(context.Context) ([]byte, error) {
+		return load()
+	})
+}
+
+// LoadContext is Load on behalf of ctx, which is passed to load. It returns
+// ctx.Err() if ctx is done while waiting for a load started by another caller.
+func (h *Handle) LoadContext(ctx context.Context, block uint64, load func(context.Context) ([]byte, error)) ([]byte, error) {
+	return h.cache.load(ctx, h.key(block), h, load)
+}
+
+// Purge removes all blocks of the image from the cache. Loads in progress
+// still return their blocks but no longer cache them.
+func (h *Handle) Purge() {
+	h.cache.purge(h)
+}
+
+// Stats returns the statistics of the image.
+func (h *Handle) Stats() Stats {
+	h.cache.mu.Lock()
+	defer h.cache.mu.Unlock()
+	return h.stats
+}
+
+// key returns the cache key of block.
+func (h *Handle) key(block uint64) Key {
+	return Key{File: h.file, IFD: h.ifd, Block: block}
+}
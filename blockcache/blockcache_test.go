@@ -0,0 +1,236 @@
+package blockcache
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEviction(t *testing.T) {
+	c := New(8)
+	h := c.Handle(NewFileID(), 0)
+	h.Add(0, make([]byte, 4))
+	h.Add(1, make([]byte, 4))
+	h.Get(0) // 1 is now least recently used
+	h.Add(2, make([]byte, 4))
+
+	for block, want := range []bool{true, false, true} {
+		if got := h.Contains(uint64(block)); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", block, got, want)
+		}
+	}
+	s := h.Stats()
+	if s.Blocks != 2 || s.Bytes != 8 || s.Evictions != 1 || s.Hits != 1 {
+		t.Errorf("Stats() = %+v, want 2 blocks, 8 bytes, 1 eviction, 1 hit", s)
+	}
+	if c.Stats() != s {
+		t.Errorf("cache Stats() = %+v, want %+v", c.Stats(), s)
+	}
+}
+
+func TestKeepsLastBlock(t *testing.T) {
+	c := New(0)
+	h := c.Handle(NewFileID(), 0)
+	h.Add(0, make([]byte, 4))
+	h.Add(1, make([]byte, 4))
+	if h.Contains(0) || !h.Contains(1) {
+		t.Errorf("Contains = %v, %v, want only the last block", h.Contains(0), h.Contains(1))
+	}
+
+	c.SetBudget(-1)
+	if !h.Contains(1) {
+		t.Errorf("SetBudget evicted the last block")
+	}
+}
+
+func TestHandlesShareBudget(t *testing.T) {
+	c := New(8)
+	file := NewFileID()
+	a, b := c.Handle(file, 0), c.Handle(file, 1)
+	a.Add(0, make([]byte, 4))
+	b.Add(0, make([]byte, 4))
+	if !a.Contains(0) || !b.Contains(0) {
+		t.Fatalf("blocks of different directories collide")
+	}
+	b.Add(1, make([]byte, 4))
+	if a.Contains(0) {
+		t.Errorf("block of a not evicted for b")
+	}
+	if s := a.Stats(); s.Evictions != 1 || s.Blocks != 0 {
+		t.Errorf("a.Stats() = %+v, want the eviction counted for a", s)
+	}
+
+	b.Purge()
+	if s := c.Stats(); s.Blocks != 0 || s.Bytes != 0 {
+		t.Errorf("Stats() after Purge = %+v, want empty", s)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	c := New(1 << 20)
+	file := NewFileID()
+	a, b := c.Handle(file, 0), c.Handle(file, 1)
+	for block := range uint64(4) {
+		a.Add(block, make([]byte, 4))
+		b.Add(block, make([]byte, 4))
+	}
+	a.Add(0, make([]byte, 2)) // replaced blocks are not purged twice
+
+	a.Purge()
+	for block := range uint64(4) {
+		if a.Contains(block) || !b.Contains(block) {
+			t.Errorf("block %d after Purge: a %v, b %v, want only b", block, a.Contains(block), b.Contains(block))
+		}
+	}
+	if s := a.Stats(); s.Blocks != 0 || s.Bytes != 0 {
+		t.Errorf("a.Stats() = %+v, want empty", s)
+	}
+	if s := c.Stats(); s.Blocks != 4 || s.Bytes != 16 {
+		t.Errorf("Stats() = %+v, want the 4 blocks of b", s)
+	}
+	if len(a.items) != 0 || len(b.items) != 4 {
+		t.Errorf("handles track %d and %d blocks, want 0 and 4", len(a.items), len(b.items))
+	}
+
+	// Evicted blocks are no longer tracked by their handle.
+	c.SetBudget(8)
+	if len(b.items) != 2 {
+		t.Errorf("b tracks %d blocks after eviction, want 2", len(b.items))
+	}
+}
+
+func TestLoadDeduplicates(t *testing.T) {
+	h := New(1<<20).Handle(NewFileID(), 0)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	load := func() ([]byte, error) {
+		calls.Add(1)
+		<-release
+		return []byte{1}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if data, err := h.Load(0, load); err != nil || len(data) != 1 {
+				t.Errorf("Load() = %v, %v", data, err)
+			}
+		}()
+	}
+	// Let the goroutines pile up on the first load.
+	for h.cache.inflightLen() == 0 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("load called %d times, want 1", n)
+	}
+}
+
+func TestLoadErrorNotCached(t *testing.T) {
+	h := New(1<<20).Handle(NewFileID(), 0)
+	errLoad := errors.New("load failed")
+	if _, err := h.Load(0, func() ([]byte, error) { return nil, errLoad }); err != errLoad {
+		t.Fatalf("Load() = %v, want %v", err, errLoad)
+	}
+	if h.Contains(0) {
+		t.Fatalf("failed load was cached")
+	}
+	if data, err := h.Load(0, func() ([]byte, error) { return []byte{1}, nil }); err != nil || len(data) != 1 {
+		t.Errorf("Load() after error = %v, %v", data, err)
+	}
+}
+
+func TestLoadWaiterCancelled(t *testing.T) {
+	h := New(1<<20).Handle(NewFileID(), 0)
+	started, release := make(chan struct{}), make(chan struct{})
+	go h.Load(0, func() ([]byte, error) {
+		close(started)
+		<-release
+		return []byte{1}, nil
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := h.LoadContext(ctx, 0, func(context.Context) ([]byte, error) {
+		t.Error("waiter ran its own load")
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadContext() = %v, want context.Canceled", err)
+	}
+}
+
+func TestLoadRetriesCancelledLoad(t *testing.T) {
+	h := New(1<<20).Handle(NewFileID(), 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go h.LoadContext(ctx, 0, func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	done := make(chan error)
+	go func() {
+		_, err := h.Load(0, func() ([]byte, error) { return []byte{1}, nil })
+		done <- err
+	}()
+	for h.cache.inflightLen() == 0 {
+		runtime.Gosched()
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Load() after the shared load was cancelled = %v, want retry", err)
+	}
+}
+
+func TestPurgeDuringLoad(t *testing.T) {
+	h := New(1<<20).Handle(NewFileID(), 0)
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan []byte)
+	go func() {
+		data, _ := h.Load(0, func() ([]byte, error) {
+			close(started)
+			<-release
+			return []byte{1}, nil
+		})
+		done <- data
+	}()
+	<-started
+	h.Purge()
+	close(release)
+
+	if data := <-done; len(data) != 1 {
+		t.Errorf("Load() = %v, want the loaded block", data)
+	}
+	if h.Contains(0) {
+		t.Errorf("load finishing after Purge cached its block")
+	}
+	if s := h.Stats(); s.Blocks != 0 || s.Bytes != 0 {
+		t.Errorf("Stats() = %+v, want empty", s)
+	}
+
+	// Loads started after Purge are cached again.
+	h.Load(1, func() ([]byte, error) { return []byte{1}, nil })
+	if !h.Contains(1) {
+		t.Errorf("load after Purge not cached")
+	}
+}
+
+// inflightLen returns the number of loads in progress.
+func (c *Cache) inflightLen() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.inflight)
+}
@@ -0,0 +1,58 @@
+package tiff_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+)
+
+// cancellingReader implements tiff.ReaderAtContext and counts reads issued
+// with a context.
+type cancellingReader struct {
+	*bytes.Reader
+	withContext int
+}
+
+func (r *cancellingReader) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	r.withContext++
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestDecodeContext(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(2, 2), Blocks: [][]byte{{1, 2, 3, 4}}})
+	r := &cancellingReader{Reader: bytes.NewReader(data)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	img, err := tiff.DecodeContext(ctx, r, tiff.Options{NoFallback: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.withContext == 0 {
+		t.Errorf("directories not read through ReadAtContext")
+	}
+
+	// Pixels are read on behalf of the context of each access, not ctx.
+	cancel()
+	ti := img.(tiff.Image)
+	c, err := ti.AtContext(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := c.RGBA(); r>>8 != 4 {
+		t.Errorf("AtContext(1, 1) = %v, want gray 4", c)
+	}
+
+	if _, err := tiff.DecodeContext(ctx, bytes.NewReader(data), tiff.Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("DecodeContext(cancelled) = %v, want context.Canceled", err)
+	}
+	if err := ti.ReadRegion(ctx, ti.Bounds(), nil, make([]byte, 4), 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadRegion(cancelled) = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,151 @@
+package tiff
+
+import (
+	"context"
+	"encoding/binary"
+	"image"
+	"io"
+
+	"github.com/echoflaresat/tiff/compression"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/impl"
+	"github.com/echoflaresat/tiff/photometric"
+	"github.com/echoflaresat/tiff/planarconfig"
+	"github.com/echoflaresat/tiff/subfile"
+)
+
+// Description summarizes the structure of a TIFF file as declared by its
+// directories. The image fields describe the first directory.
+type Description struct {
+	// Config holds the width, height and color model of the first image.
+	image.Config
+
+	// ByteOrder is the byte order of the file.
+	ByteOrder binary.ByteOrder
+
+	// BigTIFF is set for BigTIFF files.
+	BigTIFF bool
+
+	Compression     compression.Type
+	Photometric     photometric.Interpretation
+	PlanarConfig    planarconfig.Type
+	SamplesPerPixel int
+	BitsPerSample   []int
+
+	// Tiled is set if the image is stored in tiles of TileWidth × TileHeight
+	// pixels; otherwise it is stored in strips of RowsPerStrip rows.
+	Tiled                 bool
+	TileWidth, TileHeight int
+	RowsPerStrip          int
+
+	// Pages is the number of full-resolution images (directories that are
+	// neither reduced-resolution overviews nor transparency masks).
+	Pages int
+
+	// Overviews is the number of reduced-resolution images.
+	Overviews int
+
+	// Directories is the number of directories in the IFD chain, up to the
+	// first one that cannot be read.
+	Directories int
+
+	// Lazy reports whether Decode reads the image with random access
+	// (returning an Image) rather than with the fallback decoder.
+	Lazy bool
+}
+
+// DecodeConfig returns the color model and dimensions of a TIFF image
+// without reading pixel data. Only the first directory is read.
+//
+// It uses the same directory parser as Decode, so it accepts BigTIFF files
+// and any compression, and reports the color model Decode produces.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	readerAt, closer, err := directoryReaderAt(r, Options{})
+	if err != nil {
+		return image.Config{}, err
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+	h, err := impl.ReadHeader(readerAt)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return config(h)
+}
+
+// config returns the color model and dimensions of the image described by h.
+func config(h impl.TiffHeader) (image.Config, error) {
+	model, err := impl.ColorModel(h)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: model, Width: h.Width, Height: h.Height}, nil
+}
+
+// DescribeImage reads the directories of a TIFF file and reports its layout,
+// compression and page count without reading pixel data. Unlike
+// DecodeConfig, it walks the whole IFD chain to count pages and overviews.
+//
+// If r implements neither io.ReaderAt nor io.ReadSeeker, it is read as far as
+// the directories require, and spooled like in Decode if they lie beyond the
+// spool threshold.
+func DescribeImage(r io.Reader) (Description, error) {
+	return DescribeImageContext(context.Background(), r)
+}
+
+// DescribeImageContext is DescribeImage with the directories read on behalf of ctx.
+func DescribeImageContext(ctx context.Context, r io.Reader) (Description, error) {
+	readerAt, closer, err := directoryReaderAt(r, Options{})
+	if err != nil {
+		return Description{}, err
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+	readerAt = impl.WithContext(ctx, readerAt)
+
+	fileHeader, err := ifd.ReadHeader(readerAt)
+	if err != nil {
+		return Description{}, err
+	}
+	headers, err := impl.ReadHeaders(readerAt)
+	if err != nil {
+		return Description{}, err
+	}
+	h := headers[0]
+
+	cfg, err := config(h)
+	if err != nil {
+		return Description{}, err
+	}
+
+	d := Description{
+		Config:          cfg,
+		ByteOrder:       fileHeader.ByteOrder,
+		BigTIFF:         fileHeader.BigTIFF,
+		Compression:     h.Compression,
+		Photometric:     h.Photometric,
+		PlanarConfig:    h.PlanarConfig,
+		SamplesPerPixel: h.SamplesPerPixel,
+		BitsPerSample:   h.BitsPerSample,
+		Tiled:           len(h.TileOffsets) > 0,
+		Directories:     len(headers),
+		Lazy:            impl.Lazy(h),
+	}
+	if d.Tiled {
+		d.TileWidth, d.TileHeight = h.TileWidth, h.TileHeight
+	} else {
+		d.RowsPerStrip = h.RowsPerStrip
+	}
+	for _, hdr := range headers {
+		switch {
+		case hdr.SubfileType.Has(subfile.Mask):
+		case hdr.SubfileType.Has(subfile.ReducedImage):
+			d.Overviews++
+		default:
+			d.Pages++
+		}
+	}
+	return d, nil
+}
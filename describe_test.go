@@ -0,0 +1,121 @@
+package tiff_test
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// pyramid returns a file with a 4×4 RGB page, its mask, a 2×2 overview and a
+// second page.
+func pyramid() []byte {
+	mask := tifftest.IFD{
+		Entries: tifftest.With(tifftest.Image(4, 4, 4, 1),
+			tifftest.Short(tifftag.BitsPerSample, 1),
+			tifftest.Long(tifftag.NewSubfileType, 4)),
+		Blocks: [][]byte{make([]byte, 4)},
+	}
+	overview := tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(2, 2), tifftest.Long(tifftag.NewSubfileType, 1)),
+		Blocks:  [][]byte{make([]byte, 4)},
+	}
+	return tifftest.Build(
+		tifftest.IFD{
+			Entries: tifftest.With(tifftest.Image(4, 4, 2, 3),
+				tifftest.Short(tifftag.TileWidth, 16),
+				tifftest.Short(tifftag.TileLength, 16)),
+			Blocks: [][]byte{make([]byte, 16*16*3)},
+			Tiled:  true,
+		},
+		mask,
+		overview,
+		tifftest.IFD{Entries: tifftest.Gray(1, 1), Blocks: [][]byte{{0}}},
+	)
+}
+
+// limitedReader fails reads at or beyond limit.
+type limitedReader struct {
+	*bytes.Reader
+	limit int64
+}
+
+func (r limitedReader) ReadAt(p []byte, off int64) (int, error) {
+	if off+int64(len(p)) > r.limit {
+		return 0, errors.New("read beyond the first directory")
+	}
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestDescribeImage(t *testing.T) {
+	d, err := tiff.DescribeImage(bytes.NewReader(pyramid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Width != 4 || d.Height != 4 || d.ColorModel != color.RGBAModel {
+		t.Errorf("Config = %+v", d.Config)
+	}
+	if !d.Tiled || d.TileWidth != 16 || d.RowsPerStrip != 0 || d.SamplesPerPixel != 3 || !d.Lazy {
+		t.Errorf("layout = %+v", d)
+	}
+	if d.Pages != 2 || d.Overviews != 1 || d.Directories != 4 {
+		t.Errorf("Pages %d, Overviews %d, Directories %d", d.Pages, d.Overviews, d.Directories)
+	}
+}
+
+func TestDecodeConfigReadsFirstDirectory(t *testing.T) {
+	data := pyramid()
+	dirs, err := ifd.ReadAll(bytes.NewReader(data), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := limitedReader{Reader: bytes.NewReader(data), limit: dirs[1].Offset}
+
+	cfg, err := tiff.DecodeConfig(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 4 || cfg.Height != 4 || cfg.ColorModel != color.RGBAModel {
+		t.Errorf("DecodeConfig() = %+v", cfg)
+	}
+
+	// DescribeImage walks the chain, which ends at the unreadable directory.
+	d, err := tiff.DescribeImage(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Directories != 1 || d.Pages != 1 {
+		t.Errorf("Directories %d, Pages %d, want 1", d.Directories, d.Pages)
+	}
+}
+
+func TestDecodeConfigInvalid(t *testing.T) {
+	if _, err := tiff.DecodeConfig(bytes.NewReader([]byte("not a tiff"))); err == nil {
+		t.Errorf("DecodeConfig() succeeded")
+	}
+	if _, err := tiff.DecodeConfig(io.LimitReader(bytes.NewReader(pyramid()), 16)); err == nil {
+		t.Errorf("DecodeConfig() of a truncated file succeeded")
+	}
+}
+
+func TestInvalidSamplesPerPixel(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(1, 1), tifftest.Long(tifftag.SamplesPerPixel, 1<<31)),
+		Blocks:  [][]byte{{0}},
+	})
+	if _, err := tiff.DescribeImage(bytes.NewReader(data)); err == nil {
+		t.Errorf("DescribeImage() succeeded")
+	}
+	if _, err := tiff.DecodeConfig(bytes.NewReader(data)); err == nil {
+		t.Errorf("DecodeConfig() succeeded")
+	}
+	if _, err := tiff.Decode(bytes.NewReader(data)); err == nil {
+		t.Errorf("Decode() succeeded")
+	}
+}
@@ -0,0 +1,224 @@
+// Package exif parses the EXIF (tag 34665) and GPS (tag 34853) sub-directories
+// referenced from a TIFF image file directory, as written by cameras and in
+// DNG files, into structured metadata.
+//
+// The raw sub-directories remain available through the Directory fields for
+// tags without a dedicated field.
+//
+// Reference: https://www.cipa.jp/std/documents/e/DC-008-2012_E.pdf
+package exif
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ErrNotFound is returned when the directory has no pointer to the requested sub-directory.
+var ErrNotFound = errors.New("exif: sub-directory not present")
+
+// dateLayout is the EXIF date and time format.
+const dateLayout = "2006:01:02 15:04:05"
+
+// Exif holds the camera metadata of the EXIF sub-directory.
+// Fields are zero when the corresponding tag is absent.
+type Exif struct {
+	// Directory is the raw EXIF sub-directory.
+	Directory *ifd.Directory
+
+	ExposureTime    float64 // seconds
+	FNumber         float64
+	ExposureProgram int
+	ExposureBias    float64 // EV
+	ISO             int
+	MeteringMode    int
+	Flash           int
+	FocalLength     float64 // millimeters
+	FocalLength35mm int     // millimeters
+
+	LensMake          string
+	LensModel         string
+	LensSerialNumber  string
+	LensSpecification []float64 // min/max focal length, min F number at min/max focal length
+	BodySerialNumber  string
+
+	// DateTimeOriginal and DateTimeDigitized include sub-second precision and
+	// the UTC offset when the corresponding tags are present. Without an offset
+	// tag the recorded wall-clock time is returned in UTC.
+	DateTimeOriginal  time.Time
+	DateTimeDigitized time.Time
+}
+
+// GPS holds the position metadata of the GPS sub-directory.
+type GPS struct {
+	// Directory is the raw GPS sub-directory.
+	Directory *ifd.Directory
+
+	// Latitude and Longitude are signed decimal degrees (south and west negative).
+	// They are only valid if HasPosition is true.
+	Latitude, Longitude float64
+	HasPosition         bool
+
+	// Altitude is in meters, negative below sea level. It is only valid if HasAltitude is true.
+	Altitude    float64
+	HasAltitude bool
+
+	// Time is the UTC time of the fix from GPSDateStamp and GPSTimeStamp,
+	// or the zero time if either is absent.
+	Time time.Time
+
+	// MapDatum is the geodetic datum, typically "WGS-84".
+	MapDatum string
+
+	// Satellites describes the satellites used for the measurement.
+	Satellites string
+}
+
+// ReadExif follows the EXIF IFD pointer of d and parses the sub-directory.
+func ReadExif(r io.ReaderAt, d *ifd.Directory) (*Exif, error) {
+	sub, err := readSubDirectory(r, d, tifftag.ExifIFD)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExif(sub), nil
+}
+
+// ReadGPS follows the GPS IFD pointer of d and parses the sub-directory.
+func ReadGPS(r io.ReaderAt, d *ifd.Directory) (*GPS, error) {
+	sub, err := readSubDirectory(r, d, tifftag.GPSIFD)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGPS(sub)
+}
+
+// readSubDirectory reads the directory referenced by the pointer tag of d.
+func readSubDirectory(r io.ReaderAt, d *ifd.Directory, tag tifftag.Tag) (*ifd.Directory, error) {
+	offset, ok := d.Uint(tag)
+	if !ok || offset == 0 {
+		return nil, ErrNotFound
+	}
+	sub, err := ifd.ReadDirectory(r, d.Header(), int64(offset))
+	if err != nil {
+		return nil, fmt.Errorf("exif: reading %s: %w", tag, err)
+	}
+	return sub, nil
+}
+
+// ParseExif extracts the camera metadata from an EXIF sub-directory.
+func ParseExif(d *ifd.Directory) *Exif {
+	e := &Exif{Directory: d}
+
+	float := func(tag tifftag.Tag) float64 {
+		v, _ := d.Float(tag)
+		return v
+	}
+	integer := func(tag tifftag.Tag) int {
+		v, _ := d.Int(tag)
+		return int(v)
+	}
+	text := func(tag tifftag.Tag) string {
+		v, _ := d.ASCII(tag)
+		return strings.TrimSpace(v)
+	}
+
+	e.ExposureTime = float(tifftag.ExposureTime)
+	e.FNumber = float(tifftag.FNumber)
+	e.ExposureProgram = integer(tifftag.ExposureProgram)
+	e.ExposureBias = float(tifftag.ExposureBiasValue)
+	e.ISO = integer(tifftag.ISOSpeedRatings)
+	e.MeteringMode = integer(tifftag.MeteringMode)
+	e.Flash = integer(tifftag.Flash)
+	e.FocalLength = float(tifftag.FocalLength)
+	e.FocalLength35mm = integer(tifftag.FocalLengthIn35mmFilm)
+	e.LensMake = text(tifftag.LensMake)
+	e.LensModel = text(tifftag.LensModel)
+	e.LensSerialNumber = text(tifftag.LensSerialNumber)
+	e.LensSpecification, _ = d.Floats(tifftag.LensSpecification)
+	e.BodySerialNumber = text(tifftag.BodySerialNumber)
+	e.DateTimeOriginal = parseDateTime(text(tifftag.DateTimeOriginal), text(tifftag.SubSecTimeOriginal), text(tifftag.OffsetTimeOriginal))
+	e.DateTimeDigitized = parseDateTime(text(tifftag.DateTimeDigitized), "", text(tifftag.OffsetTimeDigitized))
+	return e
+}
+
+// ParseGPS extracts the position metadata from a GPS sub-directory.
+func ParseGPS(d *ifd.Directory) (*GPS, error) {
+	g := &GPS{Directory: d}
+
+	lat, latOK := d.Floats(tifftag.GPSLatitude)
+	lon, lonOK := d.Floats(tifftag.GPSLongitude)
+	if latOK && lonOK {
+		var err error
+		if g.Latitude, err = degrees(lat); err != nil {
+			return nil, fmt.Errorf("exif: GPSLatitude: %w", err)
+		}
+		if g.Longitude, err = degrees(lon); err != nil {
+			return nil, fmt.Errorf("exif: GPSLongitude: %w", err)
+		}
+		if ref, _ := d.ASCII(tifftag.GPSLatitudeRef); strings.EqualFold(ref, "S") {
+			g.Latitude = -g.Latitude
+		}
+		if ref, _ := d.ASCII(tifftag.GPSLongitudeRef); strings.EqualFold(ref, "W") {
+			g.Longitude = -g.Longitude
+		}
+		g.HasPosition = true
+	}
+
+	if alt, ok := d.Float(tifftag.GPSAltitude); ok {
+		g.Altitude, g.HasAltitude = alt, true
+		if ref, _ := d.Uint(tifftag.GPSAltitudeRef); ref == 1 {
+			g.Altitude = -alt
+		}
+	}
+
+	date, dateOK := d.ASCII(tifftag.GPSDateStamp)
+	hms, timeOK := d.Floats(tifftag.GPSTimeStamp)
+	if dateOK && timeOK && len(hms) == 3 {
+		if day, err := time.Parse("2006:01:02", strings.TrimSpace(date)); err == nil {
+			secs := hms[0]*3600 + hms[1]*60 + hms[2]
+			g.Time = day.Add(time.Duration(secs * float64(time.Second)))
+		}
+	}
+
+	g.MapDatum, _ = d.ASCII(tifftag.GPSMapDatum)
+	g.Satellites, _ = d.ASCII(tifftag.GPSSatellites)
+	return g, nil
+}
+
+// degrees converts a degrees, minutes, seconds triple to decimal degrees.
+func degrees(dms []float64) (float64, error) {
+	if len(dms) != 3 {
+		return 0, fmt.Errorf("want 3 values, got %d", len(dms))
+	}
+	return dms[0] + dms[1]/60 + dms[2]/3600, nil
+}
+
+// parseDateTime parses an EXIF date and time with optional sub-second digits
+// and UTC offset ("+01:00"). It returns the zero time if s is empty or invalid.
+func parseDateTime(s, subsec, offset string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	loc := time.UTC
+	if offset != "" {
+		if t, err := time.Parse("-07:00", offset); err == nil {
+			_, secs := t.Zone()
+			loc = time.FixedZone(offset, secs)
+		}
+	}
+	t, err := time.ParseInLocation(dateLayout, s, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	if subsec != "" {
+		if frac, err := time.ParseDuration("0." + subsec + "s"); err == nil {
+			t = t.Add(frac)
+		}
+	}
+	return t
+}
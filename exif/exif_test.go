@@ -0,0 +1,142 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// rationals returns a RATIONAL entry holding the given num/den pairs.
+func rationals(tag tifftag.Tag, pairs ...uint64) tifftest.Entry {
+	return tifftest.Entry{Tag: tag, Type: fieldtype.Rational, Values: pairs}
+}
+
+// camera returns a file with EXIF and GPS sub-directories and its first
+// directory.
+func camera(t *testing.T) (*bytes.Reader, *ifd.Directory) {
+	t.Helper()
+	r := bytes.NewReader(tifftest.Build(tifftest.IFD{
+		Entries: tifftest.Gray(1, 1),
+		Blocks:  [][]byte{{0}},
+		SubIFDs: map[tifftag.Tag]tifftest.IFD{
+			tifftag.ExifIFD: {Entries: []tifftest.Entry{
+				rationals(tifftag.ExposureTime, 1, 250),
+				rationals(tifftag.FNumber, 28, 10),
+				tifftest.Short(tifftag.ISOSpeedRatings, 400),
+				{Tag: tifftag.ExposureBiasValue, Type: fieldtype.SRational, Values: []uint64{uint64(0xffffffff), 3}},
+				rationals(tifftag.FocalLength, 50, 1),
+				tifftest.ASCII(tifftag.LensModel, " 50mm F1.8 "),
+				rationals(tifftag.LensSpecification, 50, 1, 50, 1, 18, 10, 18, 10),
+				tifftest.ASCII(tifftag.DateTimeOriginal, "2024:05:06 07:08:09"),
+				tifftest.ASCII(tifftag.SubSecTimeOriginal, "25"),
+				tifftest.ASCII(tifftag.OffsetTimeOriginal, "+02:00"),
+				tifftest.ASCII(tifftag.DateTimeDigitized, "2024:05:06 07:08:09"),
+			}},
+			tifftag.GPSIFD: {Entries: []tifftest.Entry{
+				tifftest.ASCII(tifftag.GPSLatitudeRef, "S"),
+				rationals(tifftag.GPSLatitude, 33, 1, 30, 1, 36, 1),
+				tifftest.ASCII(tifftag.GPSLongitudeRef, "W"),
+				rationals(tifftag.GPSLongitude, 70, 1, 15, 1, 0, 1),
+				{Tag: tifftag.GPSAltitudeRef, Type: fieldtype.Byte, Raw: []byte{1}},
+				rationals(tifftag.GPSAltitude, 125, 10),
+				rationals(tifftag.GPSTimeStamp, 12, 1, 30, 1, 15, 1),
+				tifftest.ASCII(tifftag.GPSDateStamp, "2024:05:06"),
+				tifftest.ASCII(tifftag.GPSMapDatum, "WGS-84"),
+			}},
+		},
+	}))
+	dirs, err := ifd.ReadAll(r, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, dirs[0]
+}
+
+func TestReadExif(t *testing.T) {
+	r, d := camera(t)
+	e, err := ReadExif(r, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ExposureTime != 1.0/250 || e.FNumber != 2.8 || e.ISO != 400 || e.FocalLength != 50 {
+		t.Errorf("exposure = %v s, f/%v, ISO %d, %v mm", e.ExposureTime, e.FNumber, e.ISO, e.FocalLength)
+	}
+	if e.ExposureBias != -1.0/3 {
+		t.Errorf("ExposureBias = %v", e.ExposureBias)
+	}
+	if e.LensModel != "50mm F1.8" || len(e.LensSpecification) != 4 || e.LensSpecification[2] != 1.8 {
+		t.Errorf("lens = %q %v", e.LensModel, e.LensSpecification)
+	}
+	want := time.Date(2024, 5, 6, 7, 8, 9, 250_000_000, time.FixedZone("+02:00", 2*3600))
+	if !e.DateTimeOriginal.Equal(want) {
+		t.Errorf("DateTimeOriginal = %v, want %v", e.DateTimeOriginal, want)
+	}
+	if got := e.DateTimeDigitized; !got.Equal(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)) {
+		t.Errorf("DateTimeDigitized = %v, want UTC wall-clock time", got)
+	}
+	if !e.Directory.Has(tifftag.LensModel) {
+		t.Errorf("raw directory misses LensModel")
+	}
+}
+
+func TestReadGPS(t *testing.T) {
+	r, d := camera(t)
+	g, err := ReadGPS(r, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasPosition || g.Latitude != -33.51 || g.Longitude != -70.25 {
+		t.Errorf("position = %v, %v, %v", g.Latitude, g.Longitude, g.HasPosition)
+	}
+	if !g.HasAltitude || g.Altitude != -12.5 {
+		t.Errorf("altitude = %v, %v", g.Altitude, g.HasAltitude)
+	}
+	if want := time.Date(2024, 5, 6, 12, 30, 15, 0, time.UTC); !g.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", g.Time, want)
+	}
+	if g.MapDatum != "WGS-84" {
+		t.Errorf("MapDatum = %q", g.MapDatum)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(1, 1), Blocks: [][]byte{{0}}})
+	dirs, err := ifd.ReadAll(bytes.NewReader(data), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadExif(bytes.NewReader(data), dirs[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReadExif() = %v, want ErrNotFound", err)
+	}
+	if _, err := ReadGPS(bytes.NewReader(data), dirs[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ReadGPS() = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseGPSInvalid(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{Entries: []tifftest.Entry{
+		rationals(tifftag.GPSLatitude, 33, 1),
+		rationals(tifftag.GPSLongitude, 70, 1, 15, 1, 0, 1),
+	}})
+	dirs, err := ifd.ReadAll(bytes.NewReader(data), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseGPS(dirs[0]); err == nil {
+		t.Errorf("ParseGPS() accepted a latitude with one value")
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	for _, s := range []string{"", "2024-05-06 07:08:09", "    :  :     :  :  "} {
+		if got := parseDateTime(s, "", ""); !got.IsZero() {
+			t.Errorf("parseDateTime(%q) = %v, want zero time", s, got)
+		}
+	}
+}
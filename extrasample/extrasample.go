@@ -0,0 +1,39 @@
+// Package extrasample defines the TIFF ExtraSamples tag values,
+// which describe the meaning of samples beyond the color channels of a pixel.
+//
+// This corresponds to TIFF tag 338:
+// https://www.awaresystems.be/imaging/tiff/tifftags/extrasamples.html
+package extrasample
+
+import "fmt"
+
+// Type represents a single TIFF ExtraSamples value (tag 338).
+type Type int
+
+const (
+	// Unspecified (0) means the extra sample carries unspecified data.
+	Unspecified Type = 0
+
+	// AssociatedAlpha (1) means the sample is alpha and the color channels
+	// are premultiplied by it.
+	AssociatedAlpha Type = 1
+
+	// UnassociatedAlpha (2) means the sample is alpha and the color channels
+	// are stored without premultiplication.
+	UnassociatedAlpha Type = 2
+)
+
+// String returns a human-readable name for the extra sample type.
+// If the value is unknown, it returns a formatted fallback string.
+func (t Type) String() string {
+	switch t {
+	case Unspecified:
+		return "Unspecified"
+	case AssociatedAlpha:
+		return "AssociatedAlpha"
+	case UnassociatedAlpha:
+		return "UnassociatedAlpha"
+	default:
+		return fmt.Sprintf("ExtraSample(%d)", int(t))
+	}
+}
@@ -0,0 +1,117 @@
+// Package fieldtype defines the TIFF field (data) types used by IFD entries,
+// such as SHORT, LONG and RATIONAL, including the BigTIFF 64-bit types.
+//
+// Reference: https://www.awaresystems.be/imaging/tiff/specification/TIFF6.pdf (section 2)
+package fieldtype
+
+import "fmt"
+
+// Type represents the data type of a TIFF directory entry.
+type Type uint16
+
+const (
+	// Byte is an 8-bit unsigned integer.
+	Byte Type = 1
+
+	// ASCII is an 8-bit byte holding a 7-bit ASCII code; strings are NUL-terminated.
+	ASCII Type = 2
+
+	// Short is a 16-bit unsigned integer.
+	Short Type = 3
+
+	// Long is a 32-bit unsigned integer.
+	Long Type = 4
+
+	// Rational is two Longs: a numerator and a denominator.
+	Rational Type = 5
+
+	// SByte is an 8-bit signed integer.
+	SByte Type = 6
+
+	// Undefined is an 8-bit byte with field-specific meaning.
+	Undefined Type = 7
+
+	// SShort is a 16-bit signed integer.
+	SShort Type = 8
+
+	// SLong is a 32-bit signed integer.
+	SLong Type = 9
+
+	// SRational is two SLongs: a numerator and a denominator.
+	SRational Type = 10
+
+	// Float is a single precision IEEE floating point value.
+	Float Type = 11
+
+	// Double is a double precision IEEE floating point value.
+	Double Type = 12
+
+	// IFD is a 32-bit offset to a sub-directory.
+	IFD Type = 13
+
+	// Long8 is a 64-bit unsigned integer (BigTIFF).
+	Long8 Type = 16
+
+	// SLong8 is a 64-bit signed integer (BigTIFF).
+	SLong8 Type = 17
+
+	// IFD8 is a 64-bit offset to a sub-directory (BigTIFF).
+	IFD8 Type = 18
+)
+
+// Size returns the size in bytes of a single value of type t,
+// or 0 if t is not a known field type.
+func (t Type) Size() int {
+	switch t {
+	case Byte, ASCII, SByte, Undefined:
+		return 1
+	case Short, SShort:
+		return 2
+	case Long, SLong, Float, IFD:
+		return 4
+	case Rational, SRational, Double, Long8, SLong8, IFD8:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// String returns the name of the field type as used in the TIFF specification.
+func (t Type) String() string {
+	switch t {
+	case Byte:
+		return "BYTE"
+	case ASCII:
+		return "ASCII"
+	case Short:
+		return "SHORT"
+	case Long:
+		return "LONG"
+	case Rational:
+		return "RATIONAL"
+	case SByte:
+		return "SBYTE"
+	case Undefined:
+		return "UNDEFINED"
+	case SShort:
+		return "SSHORT"
+	case SLong:
+		return "SLONG"
+	case SRational:
+		return "SRATIONAL"
+	case Float:
+		return "FLOAT"
+	case Double:
+		return "DOUBLE"
+	case IFD:
+		return "IFD"
+	case Long8:
+		return "LONG8"
+	case SLong8:
+		return "SLONG8"
+	case IFD8:
+		return "IFD8"
+	default:
+		return fmt.Sprintf("FieldType(%d)", int(t))
+	}
+}
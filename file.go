@@ -0,0 +1,56 @@
+package tiff
+
+import (
+	"image"
+	"io"
+
+	"github.com/echoflaresat/tiff/impl"
+	"github.com/echoflaresat/tiff/mmap"
+)
+
+// ErrClosed is returned when pixel data of a closed image is read.
+var ErrClosed = impl.ErrClosed
+
+// File is an image decoded from a file that it owns, as returned by Open.
+// The embedded image reads pixel data from the file on demand until Close.
+type File struct {
+	image.Image
+	file *mmap.File
+}
+
+// Open opens the named file and decodes its first image like Decode.
+// On Linux the file is memory-mapped, so uncompressed strips and tiles are
+// read in place (see package mmap).
+//
+// The returned File must be closed to release the file and the cached blocks
+// of the image. Use f.Image.(Image) for band and metadata access.
+func Open(path string) (*File, error) {
+	return OpenWithOptions(path, Options{})
+}
+
+// OpenWithOptions opens the named file like Open, applying opts.
+func OpenWithOptions(path string, opts Options) (*File, error) {
+	f, err := mmap.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	img, err := DecodeWithOptions(f, opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &File{Image: img, file: f}, nil
+}
+
+// Close closes the image and the file. Pixel reads afterwards return
+// ErrClosed, and At panics; images decoded by the fallback decoder are held
+// in memory and stay usable.
+func (f *File) Close() error {
+	if c, ok := f.Image.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			f.file.Close()
+			return err
+		}
+	}
+	return f.file.Close()
+}
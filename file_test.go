@@ -0,0 +1,87 @@
+package tiff_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+)
+
+// writeFile writes data to a temporary file and returns its path.
+func writeFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "image.tif")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOpen(t *testing.T) {
+	data, pix := stripedGray(64, 64)
+	f, err := tiff.Open(writeFile(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := f.Image.(tiff.Image)
+	readAll(t, img, pix)
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := img.AtContext(context.Background(), 0, 0); !errors.Is(err, tiff.ErrClosed) {
+		t.Errorf("AtContext() after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	if _, err := tiff.Open(writeFile(t, []byte("not a tiff"))); err == nil {
+		t.Errorf("Open() of an invalid file succeeded")
+	}
+	if _, err := tiff.Open(filepath.Join(t.TempDir(), "missing.tif")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Open() of a missing file = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestCloseDuringReads(t *testing.T) {
+	data, pix := stripedGray(1024, 1024)
+	path := writeFile(t, data)
+	for i := 0; i < 20; i++ {
+		f, err := tiff.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img := f.Image.(tiff.Image)
+
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				dst := make([]byte, len(pix))
+				for {
+					err := img.ReadRegion(context.Background(), img.Bounds(), nil, dst, 2)
+					if errors.Is(err, tiff.ErrClosed) {
+						return
+					}
+					if err != nil {
+						t.Errorf("ReadRegion() = %v", err)
+						return
+					}
+					if _, err := img.AtContext(context.Background(), 1023, 1023); err != nil && !errors.Is(err, tiff.ErrClosed) {
+						t.Errorf("AtContext() = %v", err)
+						return
+					}
+				}
+			}()
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,177 @@
+// Package gdal parses the private TIFF tags written by GDAL: the nodata value
+// (GDAL_NODATA, tag 42113) and the XML metadata document (GDAL_METADATA,
+// tag 42112) carrying per-band statistics, scale, offset and descriptions.
+//
+// Reference: https://gdal.org/drivers/raster/gtiff.html#metadata
+package gdal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// Item is a single <Item> element of the GDAL_METADATA document.
+type Item struct {
+	Name   string `xml:"name,attr"`
+	Domain string `xml:"domain,attr"`
+	Role   string `xml:"role,attr"`
+	Value  string `xml:",chardata"`
+
+	// Sample is the zero-based band the item applies to, or -1 for dataset items.
+	Sample int `xml:"-"`
+}
+
+// Band holds the metadata GDAL records for a single band.
+type Band struct {
+	// Description is the band description (role "description"), e.g. "Red" or "NIR".
+	Description string
+
+	// Scale and Offset convert stored values to physical values:
+	// physical = stored*Scale + Offset. They default to 1 and 0.
+	Scale, Offset float64
+
+	// Unit is the unit of the physical values (role "unittype"), if any.
+	Unit string
+
+	// NoData is the nodata value of the band, or NaN if none is set.
+	// GDAL stores a single nodata value that applies to every band.
+	NoData float64
+
+	// Items holds the remaining default-domain items of the band by name,
+	// such as STATISTICS_MINIMUM, STATISTICS_MAXIMUM, STATISTICS_MEAN and STATISTICS_STDDEV.
+	Items map[string]string
+}
+
+// Statistic returns the numeric value of the STATISTICS_<name> item of the band,
+// e.g. Statistic("MEAN").
+func (b Band) Statistic(name string) (float64, bool) {
+	v, ok := b.Items["STATISTICS_"+strings.ToUpper(name)]
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	return f, err == nil
+}
+
+// Metadata holds the GDAL-specific metadata of an image.
+type Metadata struct {
+	// NoData is the nodata value from GDAL_NODATA, or NaN if the tag is absent.
+	// Use HasNoData to tell an absent tag from a NaN nodata value.
+	NoData float64
+
+	// HasNoData reports whether the GDAL_NODATA tag is present.
+	HasNoData bool
+
+	// Items holds all items of the GDAL_METADATA document in document order.
+	Items []Item
+
+	// Dataset holds the default-domain dataset items (no sample attribute) by name.
+	Dataset map[string]string
+
+	// Bands holds the metadata of each band of the image.
+	Bands []Band
+}
+
+// Parse extracts the GDAL metadata of the image described by d, which has
+// the given number of bands. Images without GDAL tags yield default band
+// metadata.
+func Parse(d *ifd.Directory, bands int) (*Metadata, error) {
+	m := &Metadata{NoData: math.NaN(), Dataset: map[string]string{}}
+
+	if s, ok := d.ASCII(tifftag.GDALNoData); ok {
+		v, err := ParseNoData(s)
+		if err != nil {
+			return nil, err
+		}
+		m.NoData, m.HasNoData = v, true
+	}
+
+	m.Bands = make([]Band, bands)
+	for i := range m.Bands {
+		m.Bands[i] = Band{Scale: 1, NoData: m.NoData, Items: map[string]string{}}
+	}
+
+	if s, ok := d.ASCII(tifftag.GDALMetadata); ok {
+		items, err := parseItems(s)
+		if err != nil {
+			return nil, err
+		}
+		m.Items = items
+		for _, it := range items {
+			m.apply(it)
+		}
+	}
+	return m, nil
+}
+
+// ParseNoData parses the text of a GDAL_NODATA tag, such as "-9999", "nan" or "1e+20".
+func ParseNoData(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("gdal: invalid nodata value %q", s)
+	}
+	return v, nil
+}
+
+// parseItems decodes the items of a GDAL_METADATA document.
+func parseItems(s string) ([]Item, error) {
+	var doc struct {
+		Items []struct {
+			Item
+			Sample *int `xml:"sample,attr"`
+		} `xml:"Item"`
+	}
+	if err := xml.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, fmt.Errorf("gdal: invalid GDAL_METADATA: %w", err)
+	}
+	items := make([]Item, len(doc.Items))
+	for i, it := range doc.Items {
+		items[i] = it.Item
+		items[i].Sample = -1
+		if it.Sample != nil {
+			items[i].Sample = *it.Sample
+		}
+	}
+	return items, nil
+}
+
+// apply records a default-domain item on the dataset or on its band.
+func (m *Metadata) apply(it Item) {
+	if it.Domain != "" {
+		return
+	}
+	if it.Sample < 0 {
+		m.Dataset[it.Name] = it.Value
+		return
+	}
+	if it.Sample >= len(m.Bands) {
+		return
+	}
+	b := &m.Bands[it.Sample]
+	number := func() (float64, bool) {
+		f, err := strconv.ParseFloat(strings.TrimSpace(it.Value), 64)
+		return f, err == nil
+	}
+	switch strings.ToLower(it.Role) {
+	case "description":
+		b.Description = it.Value
+	case "scale":
+		if f, ok := number(); ok {
+			b.Scale = f
+		}
+	case "offset":
+		if f, ok := number(); ok {
+			b.Offset = f
+		}
+	case "unittype":
+		b.Unit = it.Value
+	default:
+		b.Items[it.Name] = it.Value
+	}
+}
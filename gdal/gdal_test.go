@@ -0,0 +1,102 @@
+package gdal
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// directory returns the parsed directory of a file with the given entries.
+func directory(t *testing.T, entries ...tifftest.Entry) *ifd.Directory {
+	t.Helper()
+	dirs, err := ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: entries})), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dirs[0]
+}
+
+const metadata = `<GDALMetadata>
+  <Item name="AREA_OR_POINT">Area</Item>
+  <Item name="STATISTICS_MINIMUM" sample="0">3</Item>
+  <Item name="STATISTICS_MEAN" sample="0"> 42.5 </Item>
+  <Item name="DESCRIPTION" sample="0" role="description">Red</Item>
+  <Item name="SCALE" sample="1" role="scale">0.01</Item>
+  <Item name="OFFSET" sample="1" role="offset">-5</Item>
+  <Item name="UNITTYPE" sample="1" role="unittype">K</Item>
+  <Item name="OTHER" sample="1" domain="IMAGERY">ignored</Item>
+  <Item name="OUT_OF_RANGE" sample="7">ignored</Item>
+</GDALMetadata>`
+
+func TestParse(t *testing.T) {
+	m, err := Parse(directory(t,
+		tifftest.ASCII(tifftag.GDALNoData, "-9999"),
+		tifftest.ASCII(tifftag.GDALMetadata, metadata)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.HasNoData || m.NoData != -9999 {
+		t.Errorf("NoData = %v, %v", m.NoData, m.HasNoData)
+	}
+	if len(m.Items) != 9 || m.Items[0].Sample != -1 || m.Items[1].Sample != 0 {
+		t.Errorf("Items = %+v", m.Items)
+	}
+	if m.Dataset["AREA_OR_POINT"] != "Area" {
+		t.Errorf("Dataset = %v", m.Dataset)
+	}
+	if len(m.Bands) != 2 {
+		t.Fatalf("%d bands, want 2", len(m.Bands))
+	}
+
+	red := m.Bands[0]
+	if red.Description != "Red" || red.Scale != 1 || red.Offset != 0 || red.NoData != -9999 {
+		t.Errorf("band 0 = %+v", red)
+	}
+	if v, ok := red.Statistic("mean"); !ok || v != 42.5 {
+		t.Errorf("Statistic(mean) = %v, %v", v, ok)
+	}
+	if _, ok := red.Statistic("stddev"); ok {
+		t.Errorf("Statistic(stddev) found")
+	}
+
+	b := m.Bands[1]
+	if b.Scale != 0.01 || b.Offset != -5 || b.Unit != "K" || len(b.Items) != 0 {
+		t.Errorf("band 1 = %+v", b)
+	}
+}
+
+func TestParseWithoutTags(t *testing.T) {
+	m, err := Parse(directory(t, tifftest.Gray(1, 1)...), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.HasNoData || !math.IsNaN(m.NoData) || !math.IsNaN(m.Bands[0].NoData) || m.Bands[0].Scale != 1 {
+		t.Errorf("Parse() = %+v", m)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, e := range []tifftest.Entry{
+		tifftest.ASCII(tifftag.GDALNoData, "none"),
+		tifftest.ASCII(tifftag.GDALMetadata, "<GDALMetadata><Item>"),
+	} {
+		if _, err := Parse(directory(t, e), 1); err == nil {
+			t.Errorf("Parse(%v) succeeded", e.Tag)
+		}
+	}
+}
+
+func TestParseNoData(t *testing.T) {
+	for s, want := range map[string]float64{"0": 0, " 255 ": 255, "1e+20": 1e20, "-inf": math.Inf(-1)} {
+		if v, err := ParseNoData(s); err != nil || v != want {
+			t.Errorf("ParseNoData(%q) = %v, %v, want %v", s, v, err, want)
+		}
+	}
+	if v, err := ParseNoData("nan"); err != nil || !math.IsNaN(v) {
+		t.Errorf("ParseNoData(nan) = %v, %v", v, err)
+	}
+}
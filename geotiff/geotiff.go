@@ -0,0 +1,293 @@
+// Package geotiff parses GeoTIFF georeferencing information from a TIFF
+// image file directory: the raster-to-model transformation (ModelPixelScale,
+// ModelTiepoint or ModelTransformation) and the GeoKey directory with its
+// DOUBLE and ASCII parameter tags.
+//
+// Reference: https://docs.ogc.org/is/19-008r4/19-008r4.html
+package geotiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ErrNotGeoreferenced is returned by Parse when the directory carries none
+// of the GeoTIFF tags.
+var ErrNotGeoreferenced = errors.New("geotiff: image is not georeferenced")
+
+// RasterType is the value of GTRasterTypeGeoKey.
+type RasterType int
+
+const (
+	// PixelIsArea (1) means a pixel covers an area; raster coordinates refer to its corner.
+	PixelIsArea RasterType = 1
+
+	// PixelIsPoint (2) means a pixel is a point sample; raster coordinates refer to its center.
+	PixelIsPoint RasterType = 2
+)
+
+// String returns the name of the raster type.
+func (r RasterType) String() string {
+	switch r {
+	case PixelIsArea:
+		return "PixelIsArea"
+	case PixelIsPoint:
+		return "PixelIsPoint"
+	default:
+		return fmt.Sprintf("RasterType(%d)", int(r))
+	}
+}
+
+// ModelType is the value of GTModelTypeGeoKey.
+type ModelType int
+
+const (
+	// ModelTypeProjected (1) means model coordinates are in a projected CRS.
+	ModelTypeProjected ModelType = 1
+
+	// ModelTypeGeographic (2) means model coordinates are geographic (longitude, latitude).
+	ModelTypeGeographic ModelType = 2
+
+	// ModelTypeGeocentric (3) means model coordinates are geocentric cartesian.
+	ModelTypeGeocentric ModelType = 3
+)
+
+// String returns the name of the model type.
+func (m ModelType) String() string {
+	switch m {
+	case ModelTypeProjected:
+		return "Projected"
+	case ModelTypeGeographic:
+		return "Geographic"
+	case ModelTypeGeocentric:
+		return "Geocentric"
+	default:
+		return fmt.Sprintf("ModelType(%d)", int(m))
+	}
+}
+
+// UserDefined is the GeoKey value denoting a user-defined (non-EPSG) code.
+const UserDefined = 32767
+
+// Key is a single entry of the GeoKey directory.
+// Exactly one of Shorts, Doubles or ASCII is set, depending on where the
+// value is stored (inline or GeoKeyDirectory, GeoDoubleParams, GeoASCIIParams).
+type Key struct {
+	ID       KeyID
+	Location tifftag.Tag // 0 for values stored inline in the directory
+	Shorts   []uint16
+	Doubles  []float64
+	ASCII    string
+}
+
+// Tiepoint maps the raster point (I, J, K) to the model point (X, Y, Z).
+type Tiepoint struct {
+	I, J, K float64
+	X, Y, Z float64
+}
+
+// Georeference holds the GeoTIFF georeferencing of an image.
+type Georeference struct {
+	// GeoTransform is the affine transformation from pixel to model coordinates
+	// in GDAL order:
+	//
+	//	X = GeoTransform[0] + col*GeoTransform[1] + row*GeoTransform[2]
+	//	Y = GeoTransform[3] + col*GeoTransform[4] + row*GeoTransform[5]
+	//
+	// where (col, row) = (0, 0) is the top-left corner of the top-left pixel,
+	// regardless of RasterType; PixelIsPoint rasters are shifted by half a pixel.
+	// It is only valid if HasGeoTransform is true.
+	GeoTransform [6]float64
+
+	// HasGeoTransform reports whether an affine transformation could be
+	// derived, i.e. the image has ModelTransformation, or ModelPixelScale
+	// with a tie point. Images georeferenced by multiple tie points (ground
+	// control points) only have Tiepoints.
+	HasGeoTransform bool
+
+	// PixelScale is the ModelPixelScale tag (ScaleX, ScaleY, ScaleZ), if present.
+	PixelScale []float64
+
+	// Tiepoints holds the ModelTiepoint tag.
+	Tiepoints []Tiepoint
+
+	// Transformation is the ModelTransformation 4x4 matrix in row-major order, if present.
+	Transformation []float64
+
+	// Version is the GeoKey directory version, key revision and minor revision.
+	Version [3]int
+
+	// ModelType is the GTModelTypeGeoKey value, or 0 if absent.
+	ModelType ModelType
+
+	// RasterType is the GTRasterTypeGeoKey value; it defaults to PixelIsArea.
+	RasterType RasterType
+
+	// ProjectedCRS is the EPSG code from ProjectedCSTypeGeoKey,
+	// UserDefined for custom projections, or 0 if absent.
+	ProjectedCRS int
+
+	// GeographicCRS is the EPSG code from GeographicTypeGeoKey,
+	// UserDefined for custom datums, or 0 if absent.
+	GeographicCRS int
+
+	// VerticalCRS is the EPSG code from VerticalCSTypeGeoKey, or 0 if absent.
+	VerticalCRS int
+
+	// Keys holds all GeoKeys in directory order.
+	Keys []Key
+}
+
+// Key returns the GeoKey with the given id.
+func (g *Georeference) Key(id KeyID) (Key, bool) {
+	for _, k := range g.Keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// EPSG returns the EPSG code of the CRS of the model coordinates: the
+// projected CRS if one is set, otherwise the geographic CRS. It returns 0
+// if neither is set or the CRS is user-defined.
+func (g *Georeference) EPSG() int {
+	for _, code := range []int{g.ProjectedCRS, g.GeographicCRS} {
+		if code != 0 && code != UserDefined {
+			return code
+		}
+	}
+	return 0
+}
+
+// Parse extracts the georeferencing of the image described by d.
+// It returns ErrNotGeoreferenced if d has none of the GeoTIFF tags.
+func Parse(d *ifd.Directory) (*Georeference, error) {
+	g := &Georeference{RasterType: PixelIsArea}
+	found := false
+
+	if scale, ok := d.Floats(tifftag.ModelPixelScale); ok {
+		g.PixelScale = scale
+		found = true
+	}
+	if tp, ok := d.Floats(tifftag.ModelTiepoint); ok {
+		if len(tp)%6 != 0 {
+			return nil, fmt.Errorf("geotiff: ModelTiepoint has %d values, want a multiple of 6", len(tp))
+		}
+		for i := 0; i < len(tp); i += 6 {
+			g.Tiepoints = append(g.Tiepoints, Tiepoint{I: tp[i], J: tp[i+1], K: tp[i+2], X: tp[i+3], Y: tp[i+4], Z: tp[i+5]})
+		}
+		found = true
+	}
+	if m, ok := d.Floats(tifftag.ModelTransformation); ok {
+		if len(m) != 16 {
+			return nil, fmt.Errorf("geotiff: ModelTransformation has %d values, want 16", len(m))
+		}
+		g.Transformation = m
+		found = true
+	}
+	if d.Has(tifftag.GeoKeyDirectory) {
+		if err := g.parseKeys(d); err != nil {
+			return nil, err
+		}
+		found = true
+	}
+	if !found {
+		return nil, ErrNotGeoreferenced
+	}
+
+	g.computeGeoTransform()
+	return g, nil
+}
+
+// parseKeys decodes the GeoKey directory and fills the keys and the CRS fields.
+func (g *Georeference) parseKeys(d *ifd.Directory) error {
+	dir, _ := d.Uints(tifftag.GeoKeyDirectory)
+	if len(dir) < 4 {
+		return fmt.Errorf("geotiff: GeoKeyDirectory has %d values, want at least 4", len(dir))
+	}
+	doubles, _ := d.Floats(tifftag.GeoDoubleParams)
+	asciiRaw, _ := d.Bytes(tifftag.GeoASCIIParams)
+
+	g.Version = [3]int{int(dir[0]), int(dir[1]), int(dir[2])}
+	n := int(dir[3])
+	if len(dir) < 4+4*n {
+		return fmt.Errorf("geotiff: GeoKeyDirectory declares %d keys but holds %d values", n, len(dir))
+	}
+
+	for i := 0; i < n; i++ {
+		e := dir[4+4*i : 8+4*i]
+		k := Key{ID: KeyID(e[0]), Location: tifftag.Tag(e[1])}
+		count, offset := int(e[2]), int(e[3])
+
+		switch k.Location {
+		case 0:
+			k.Shorts = []uint16{uint16(offset)}
+		case tifftag.GeoKeyDirectory:
+			if offset+count > len(dir) {
+				return fmt.Errorf("geotiff: %s out of range", k.ID)
+			}
+			for _, v := range dir[offset : offset+count] {
+				k.Shorts = append(k.Shorts, uint16(v))
+			}
+		case tifftag.GeoDoubleParams:
+			if offset+count > len(doubles) {
+				return fmt.Errorf("geotiff: %s out of range", k.ID)
+			}
+			k.Doubles = doubles[offset : offset+count]
+		case tifftag.GeoASCIIParams:
+			if offset+count > len(asciiRaw) {
+				return fmt.Errorf("geotiff: %s out of range", k.ID)
+			}
+			// Strings are terminated by '|' within the shared ASCII tag.
+			s := string(asciiRaw[offset : offset+count])
+			k.ASCII = strings.TrimRight(s, "|\x00")
+		default:
+			return fmt.Errorf("geotiff: %s stored in unsupported tag %s", k.ID, k.Location)
+		}
+		g.Keys = append(g.Keys, k)
+	}
+
+	short := func(id KeyID) int {
+		if k, ok := g.Key(id); ok && len(k.Shorts) > 0 {
+			return int(k.Shorts[0])
+		}
+		return 0
+	}
+	g.ModelType = ModelType(short(GTModelTypeGeoKey))
+	if rt := short(GTRasterTypeGeoKey); rt != 0 {
+		g.RasterType = RasterType(rt)
+	}
+	g.ProjectedCRS = short(ProjectedCSTypeGeoKey)
+	g.GeographicCRS = short(GeographicTypeGeoKey)
+	g.VerticalCRS = short(VerticalCSTypeGeoKey)
+	return nil
+}
+
+// computeGeoTransform derives the affine GeoTransform from either the
+// transformation matrix or a single tie point with pixel scale.
+func (g *Georeference) computeGeoTransform() {
+	switch {
+	case len(g.Transformation) == 16:
+		m := g.Transformation
+		g.GeoTransform = [6]float64{m[3], m[0], m[1], m[7], m[4], m[5]}
+		g.HasGeoTransform = true
+	case len(g.Tiepoints) == 1 && len(g.PixelScale) >= 2:
+		tp, sx, sy := g.Tiepoints[0], g.PixelScale[0], g.PixelScale[1]
+		g.GeoTransform = [6]float64{tp.X - tp.I*sx, sx, 0, tp.Y + tp.J*sy, 0, -sy}
+		g.HasGeoTransform = true
+	default:
+		return
+	}
+
+	if g.RasterType == PixelIsPoint {
+		// Move the origin from the center to the corner of the top-left pixel.
+		gt := &g.GeoTransform
+		gt[0] -= 0.5*gt[1] + 0.5*gt[2]
+		gt[3] -= 0.5*gt[4] + 0.5*gt[5]
+	}
+}
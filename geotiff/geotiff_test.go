@@ -0,0 +1,138 @@
+package geotiff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// directory returns the parsed directory of a file with the given entries.
+func directory(t *testing.T, entries ...tifftest.Entry) *ifd.Directory {
+	t.Helper()
+	dirs, err := ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: entries})), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dirs[0]
+}
+
+// utm32N returns the GeoTIFF tags of a north-up UTM 32N raster with 10 m
+// pixels whose top-left corner is at (500000, 5000000).
+func utm32N(rasterType uint64) []tifftest.Entry {
+	return []tifftest.Entry{
+		tifftest.Double(tifftag.ModelPixelScale, 10, 10, 0),
+		tifftest.Double(tifftag.ModelTiepoint, 0, 0, 0, 500000, 5000000, 0),
+		tifftest.Short(tifftag.GeoKeyDirectory,
+			1, 1, 0, 5,
+			uint64(GTModelTypeGeoKey), 0, 1, 1,
+			uint64(GTRasterTypeGeoKey), 0, 1, rasterType,
+			uint64(GTCitationGeoKey), uint64(tifftag.GeoASCIIParams), 8, 0,
+			uint64(ProjectedCSTypeGeoKey), 0, 1, 32632,
+			uint64(ProjFalseEastingGeoKey), uint64(tifftag.GeoDoubleParams), 1, 0,
+		),
+		tifftest.Double(tifftag.GeoDoubleParams, 500000),
+		tifftest.ASCII(tifftag.GeoASCIIParams, "UTM 32N|"),
+	}
+}
+
+func TestParse(t *testing.T) {
+	g, err := Parse(directory(t, utm32N(1)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Version != [3]int{1, 1, 0} {
+		t.Errorf("Version = %v", g.Version)
+	}
+	if g.ModelType != 1 || g.RasterType != PixelIsArea || g.ProjectedCRS != 32632 || g.EPSG() != 32632 {
+		t.Errorf("ModelType %v, RasterType %v, ProjectedCRS %d, EPSG %d", g.ModelType, g.RasterType, g.ProjectedCRS, g.EPSG())
+	}
+	if k, ok := g.Key(GTCitationGeoKey); !ok || k.ASCII != "UTM 32N" {
+		t.Errorf("citation = %+v, %v", k, ok)
+	}
+	if k, ok := g.Key(ProjFalseEastingGeoKey); !ok || len(k.Doubles) != 1 || k.Doubles[0] != 500000 {
+		t.Errorf("false easting = %+v, %v", k, ok)
+	}
+	if len(g.Keys) != 5 {
+		t.Errorf("%d keys, want 5", len(g.Keys))
+	}
+	if !g.HasGeoTransform || g.GeoTransform != [6]float64{500000, 10, 0, 5000000, 0, -10} {
+		t.Errorf("GeoTransform = %v, %v", g.GeoTransform, g.HasGeoTransform)
+	}
+}
+
+func TestParsePixelIsPoint(t *testing.T) {
+	g, err := Parse(directory(t, utm32N(uint64(PixelIsPoint))...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GeoTransform != [6]float64{499995, 10, 0, 5000005, 0, -10} {
+		t.Errorf("GeoTransform = %v, want origin shifted by half a pixel", g.GeoTransform)
+	}
+}
+
+func TestParseTransformation(t *testing.T) {
+	g, err := Parse(directory(t, tifftest.Double(tifftag.ModelTransformation,
+		2, 0.5, 0, 100,
+		0.25, -3, 0, 200,
+		0, 0, 0, 0,
+		0, 0, 0, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GeoTransform != [6]float64{100, 2, 0.5, 200, 0.25, -3} {
+		t.Errorf("GeoTransform = %v", g.GeoTransform)
+	}
+	if g.EPSG() != 0 {
+		t.Errorf("EPSG() = %d without keys", g.EPSG())
+	}
+}
+
+func TestParseGroundControlPoints(t *testing.T) {
+	g, err := Parse(directory(t, tifftest.Double(tifftag.ModelTiepoint,
+		0, 0, 0, 10, 20, 0,
+		100, 100, 0, 30, 5, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Tiepoints) != 2 || g.HasGeoTransform {
+		t.Errorf("Tiepoints = %v, HasGeoTransform = %v", g.Tiepoints, g.HasGeoTransform)
+	}
+	if _, _, err := g.PixelToModel(0, 0); !errors.Is(err, ErrNoGeoTransform) {
+		t.Errorf("PixelToModel() = %v, want ErrNoGeoTransform", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tifftest.Entry
+		want    error
+	}{
+		{"not georeferenced", tifftest.Gray(1, 1), ErrNotGeoreferenced},
+		{"short tiepoint", []tifftest.Entry{tifftest.Double(tifftag.ModelTiepoint, 0, 0, 0)}, nil},
+		{"short transformation", []tifftest.Entry{tifftest.Double(tifftag.ModelTransformation, 1, 0, 0, 0)}, nil},
+		{"truncated key directory", []tifftest.Entry{tifftest.Short(tifftag.GeoKeyDirectory, 1, 1, 0, 2, 1024, 0, 1, 1)}, nil},
+		{"key out of range", []tifftest.Entry{
+			tifftest.Short(tifftag.GeoKeyDirectory, 1, 1, 0, 1, 3082, uint64(tifftag.GeoDoubleParams), 1, 4),
+			tifftest.Double(tifftag.GeoDoubleParams, 1),
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(directory(t, tt.entries...))
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("Parse() = %v, want error %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyIDString(t *testing.T) {
+	if s := ProjectedCSTypeGeoKey.String(); s != "ProjectedCSTypeGeoKey" {
+		t.Errorf("String() = %q", s)
+	}
+}
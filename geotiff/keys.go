@@ -0,0 +1,163 @@
+package geotiff
+
+import "fmt"
+
+// KeyID identifies a GeoKey within the GeoKeyDirectory.
+type KeyID uint16
+
+// GeoKeys defined by the GeoTIFF 1.0 and 1.1 specifications.
+const (
+	GTModelTypeGeoKey              KeyID = 1024 // model type: projected, geographic or geocentric
+	GTRasterTypeGeoKey             KeyID = 1025 // raster type: PixelIsArea or PixelIsPoint
+	GTCitationGeoKey               KeyID = 1026 // general citation of the georeferencing
+	GeographicTypeGeoKey           KeyID = 2048 // EPSG code of the geographic (geodetic) CRS
+	GeogCitationGeoKey             KeyID = 2049 // citation of the geographic CRS
+	GeogGeodeticDatumGeoKey        KeyID = 2050 // EPSG code of the geodetic datum
+	GeogPrimeMeridianGeoKey        KeyID = 2051 // EPSG code of the prime meridian
+	GeogLinearUnitsGeoKey          KeyID = 2052 // EPSG code of the geographic linear units
+	GeogLinearUnitSizeGeoKey       KeyID = 2053 // size of user-defined geographic linear units in meters
+	GeogAngularUnitsGeoKey         KeyID = 2054 // EPSG code of the geographic angular units
+	GeogAngularUnitSizeGeoKey      KeyID = 2055 // size of user-defined angular units in radians
+	GeogEllipsoidGeoKey            KeyID = 2056 // EPSG code of the ellipsoid
+	GeogSemiMajorAxisGeoKey        KeyID = 2057 // semi-major axis of a user-defined ellipsoid
+	GeogSemiMinorAxisGeoKey        KeyID = 2058 // semi-minor axis of a user-defined ellipsoid
+	GeogInvFlatteningGeoKey        KeyID = 2059 // inverse flattening of a user-defined ellipsoid
+	GeogAzimuthUnitsGeoKey         KeyID = 2060 // EPSG code of the azimuth units
+	GeogPrimeMeridianLongGeoKey    KeyID = 2061 // longitude of a user-defined prime meridian
+	GeogTOWGS84GeoKey              KeyID = 2062 // datum transformation parameters to WGS 84
+	ProjectedCSTypeGeoKey          KeyID = 3072 // EPSG code of the projected CRS
+	PCSCitationGeoKey              KeyID = 3073 // citation of the projected CRS
+	ProjectionGeoKey               KeyID = 3074 // EPSG code of the projection (coordinate operation)
+	ProjCoordTransGeoKey           KeyID = 3075 // coordinate transformation method of a user-defined projection
+	ProjLinearUnitsGeoKey          KeyID = 3076 // EPSG code of the projected linear units
+	ProjLinearUnitSizeGeoKey       KeyID = 3077 // size of user-defined projected linear units in meters
+	ProjStdParallel1GeoKey         KeyID = 3078 // first standard parallel
+	ProjStdParallel2GeoKey         KeyID = 3079 // second standard parallel
+	ProjNatOriginLongGeoKey        KeyID = 3080 // longitude of the natural origin
+	ProjNatOriginLatGeoKey         KeyID = 3081 // latitude of the natural origin
+	ProjFalseEastingGeoKey         KeyID = 3082 // false easting
+	ProjFalseNorthingGeoKey        KeyID = 3083 // false northing
+	ProjFalseOriginLongGeoKey      KeyID = 3084 // longitude of the false origin
+	ProjFalseOriginLatGeoKey       KeyID = 3085 // latitude of the false origin
+	ProjFalseOriginEastingGeoKey   KeyID = 3086 // easting at the false origin
+	ProjFalseOriginNorthingGeoKey  KeyID = 3087 // northing at the false origin
+	ProjCenterLongGeoKey           KeyID = 3088 // longitude of the projection center
+	ProjCenterLatGeoKey            KeyID = 3089 // latitude of the projection center
+	ProjCenterEastingGeoKey        KeyID = 3090 // easting at the projection center
+	ProjCenterNorthingGeoKey       KeyID = 3091 // northing at the projection center
+	ProjScaleAtNatOriginGeoKey     KeyID = 3092 // scale factor at the natural origin
+	ProjScaleAtCenterGeoKey        KeyID = 3093 // scale factor at the projection center
+	ProjAzimuthAngleGeoKey         KeyID = 3094 // azimuth angle of the projection
+	ProjStraightVertPoleLongGeoKey KeyID = 3095 // longitude of the straight vertical pole
+	ProjRectifiedGridAngleGeoKey   KeyID = 3096 // angle from rectified to skewed grid
+	VerticalCSTypeGeoKey           KeyID = 4096 // EPSG code of the vertical CRS
+	VerticalCitationGeoKey         KeyID = 4097 // citation of the vertical CRS
+	VerticalDatumGeoKey            KeyID = 4098 // EPSG code of the vertical datum
+	VerticalUnitsGeoKey            KeyID = 4099 // EPSG code of the vertical units
+	CoordinateEpochGeoKey          KeyID = 5120 // coordinate epoch of a dynamic CRS (GeoTIFF 1.1)
+)
+
+// String returns the name of the GeoKey as used in the GeoTIFF specification.
+// If the key is unknown, it returns a formatted numeric identifier.
+func (k KeyID) String() string {
+	switch k {
+	case GTModelTypeGeoKey:
+		return "GTModelTypeGeoKey"
+	case GTRasterTypeGeoKey:
+		return "GTRasterTypeGeoKey"
+	case GTCitationGeoKey:
+		return "GTCitationGeoKey"
+	case GeographicTypeGeoKey:
+		return "GeographicTypeGeoKey"
+	case GeogCitationGeoKey:
+		return "GeogCitationGeoKey"
+	case GeogGeodeticDatumGeoKey:
+		return "GeogGeodeticDatumGeoKey"
+	case GeogPrimeMeridianGeoKey:
+		return "GeogPrimeMeridianGeoKey"
+	case GeogLinearUnitsGeoKey:
+		return "GeogLinearUnitsGeoKey"
+	case GeogLinearUnitSizeGeoKey:
+		return "GeogLinearUnitSizeGeoKey"
+	case GeogAngularUnitsGeoKey:
+		return "GeogAngularUnitsGeoKey"
+	case GeogAngularUnitSizeGeoKey:
+		return "GeogAngularUnitSizeGeoKey"
+	case GeogEllipsoidGeoKey:
+		return "GeogEllipsoidGeoKey"
+	case GeogSemiMajorAxisGeoKey:
+		return "GeogSemiMajorAxisGeoKey"
+	case GeogSemiMinorAxisGeoKey:
+		return "GeogSemiMinorAxisGeoKey"
+	case GeogInvFlatteningGeoKey:
+		return "GeogInvFlatteningGeoKey"
+	case GeogAzimuthUnitsGeoKey:
+		return "GeogAzimuthUnitsGeoKey"
+	case GeogPrimeMeridianLongGeoKey:
+		return "GeogPrimeMeridianLongGeoKey"
+	case GeogTOWGS84GeoKey:
+		return "GeogTOWGS84GeoKey"
+	case ProjectedCSTypeGeoKey:
+		return "ProjectedCSTypeGeoKey"
+	case PCSCitationGeoKey:
+		return "PCSCitationGeoKey"
+	case ProjectionGeoKey:
+		return "ProjectionGeoKey"
+	case ProjCoordTransGeoKey:
+		return "ProjCoordTransGeoKey"
+	case ProjLinearUnitsGeoKey:
+		return "ProjLinearUnitsGeoKey"
+	case ProjLinearUnitSizeGeoKey:
+		return "ProjLinearUnitSizeGeoKey"
+	case ProjStdParallel1GeoKey:
+		return "ProjStdParallel1GeoKey"
+	case ProjStdParallel2GeoKey:
+		return "ProjStdParallel2GeoKey"
+	case ProjNatOriginLongGeoKey:
+		return "ProjNatOriginLongGeoKey"
+	case ProjNatOriginLatGeoKey:
+		return "ProjNatOriginLatGeoKey"
+	case ProjFalseEastingGeoKey:
+		return "ProjFalseEastingGeoKey"
+	case ProjFalseNorthingGeoKey:
+		return "ProjFalseNorthingGeoKey"
+	case ProjFalseOriginLongGeoKey:
+		return "ProjFalseOriginLongGeoKey"
+	case ProjFalseOriginLatGeoKey:
+		return "ProjFalseOriginLatGeoKey"
+	case ProjFalseOriginEastingGeoKey:
+		return "ProjFalseOriginEastingGeoKey"
+	case ProjFalseOriginNorthingGeoKey:
+		return "ProjFalseOriginNorthingGeoKey"
+	case ProjCenterLongGeoKey:
+		return "ProjCenterLongGeoKey"
+	case ProjCenterLatGeoKey:
+		return "ProjCenterLatGeoKey"
+	case ProjCenterEastingGeoKey:
+		return "ProjCenterEastingGeoKey"
+	case ProjCenterNorthingGeoKey:
+		return "ProjCenterNorthingGeoKey"
+	case ProjScaleAtNatOriginGeoKey:
+		return "ProjScaleAtNatOriginGeoKey"
+	case ProjScaleAtCenterGeoKey:
+		return "ProjScaleAtCenterGeoKey"
+	case ProjAzimuthAngleGeoKey:
+		return "ProjAzimuthAngleGeoKey"
+	case ProjStraightVertPoleLongGeoKey:
+		return "ProjStraightVertPoleLongGeoKey"
+	case ProjRectifiedGridAngleGeoKey:
+		return "ProjRectifiedGridAngleGeoKey"
+	case VerticalCSTypeGeoKey:
+		return "VerticalCSTypeGeoKey"
+	case VerticalCitationGeoKey:
+		return "VerticalCitationGeoKey"
+	case VerticalDatumGeoKey:
+		return "VerticalDatumGeoKey"
+	case VerticalUnitsGeoKey:
+		return "VerticalUnitsGeoKey"
+	case CoordinateEpochGeoKey:
+		return "CoordinateEpochGeoKey"
+	default:
+		return fmt.Sprintf("GeoKey(%d)", uint16(k))
+	}
+}
@@ -0,0 +1,62 @@
+package geotiff
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// ErrNoGeoTransform is returned by the coordinate conversions when the
+// georeferencing does not define an affine GeoTransform.
+var ErrNoGeoTransform = errors.New("geotiff: no affine geotransform")
+
+// BBox is an axis-aligned bounding box in model (CRS) coordinates.
+type BBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// PixelToModel converts the pixel coordinates (col, row) to model coordinates.
+// (0, 0) is the top-left corner of the top-left pixel; pixel centers lie at
+// half-integer coordinates.
+func (g *Georeference) PixelToModel(col, row float64) (x, y float64, err error) {
+	if !g.HasGeoTransform {
+		return 0, 0, ErrNoGeoTransform
+	}
+	gt := g.GeoTransform
+	return gt[0] + col*gt[1] + row*gt[2], gt[3] + col*gt[4] + row*gt[5], nil
+}
+
+// ModelToPixel converts model coordinates to (possibly fractional) pixel
+// coordinates, inverting the GeoTransform.
+func (g *Georeference) ModelToPixel(x, y float64) (col, row float64, err error) {
+	if !g.HasGeoTransform {
+		return 0, 0, ErrNoGeoTransform
+	}
+	gt := g.GeoTransform
+	det := gt[1]*gt[5] - gt[2]*gt[4]
+	if det == 0 {
+		return 0, 0, errors.New("geotiff: geotransform is not invertible")
+	}
+	dx, dy := x-gt[0], y-gt[3]
+	return (dx*gt[5] - dy*gt[2]) / det, (dy*gt[1] - dx*gt[4]) / det, nil
+}
+
+// PixelRect returns the smallest pixel rectangle covering b. The corners of
+// b are converted to pixel space, so rotated geotransforms yield the
+// enclosing rectangle. The result is not clipped to the image bounds.
+func (g *Georeference) PixelRect(b BBox) (image.Rectangle, error) {
+	minCol, minRow := math.Inf(1), math.Inf(1)
+	maxCol, maxRow := math.Inf(-1), math.Inf(-1)
+	for _, c := range [][2]float64{{b.MinX, b.MinY}, {b.MinX, b.MaxY}, {b.MaxX, b.MinY}, {b.MaxX, b.MaxY}} {
+		col, row, err := g.ModelToPixel(c[0], c[1])
+		if err != nil {
+			return image.Rectangle{}, err
+		}
+		minCol, maxCol = math.Min(minCol, col), math.Max(maxCol, col)
+		minRow, maxRow = math.Min(minRow, row), math.Max(maxRow, row)
+	}
+	return image.Rect(
+		int(math.Floor(minCol)), int(math.Floor(minRow)),
+		int(math.Ceil(maxCol)), int(math.Ceil(maxRow)),
+	), nil
+}
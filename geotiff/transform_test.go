@@ -0,0 +1,61 @@
+package geotiff
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestPixelModelRoundTrip(t *testing.T) {
+	for _, gt := range [][6]float64{
+		{500000, 10, 0, 5000000, 0, -10},
+		{100, 2, 0.5, 200, 0.25, -3},
+	} {
+		g := &Georeference{GeoTransform: gt, HasGeoTransform: true}
+		for _, p := range [][2]float64{{0, 0}, {12.5, 7}, {-3, 1000}} {
+			x, y, err := g.PixelToModel(p[0], p[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			col, row, err := g.ModelToPixel(x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(col-p[0]) > 1e-9 || math.Abs(row-p[1]) > 1e-9 {
+				t.Errorf("%v: round trip of %v = (%v, %v)", gt, p, col, row)
+			}
+		}
+	}
+
+	g := &Georeference{GeoTransform: [6]float64{500000, 10, 0, 5000000, 0, -10}, HasGeoTransform: true}
+	if x, y, _ := g.PixelToModel(1, 2); x != 500010 || y != 4999980 {
+		t.Errorf("PixelToModel(1, 2) = %v, %v", x, y)
+	}
+}
+
+func TestModelToPixelSingular(t *testing.T) {
+	g := &Georeference{GeoTransform: [6]float64{0, 1, 1, 0, 1, 1}, HasGeoTransform: true}
+	if _, _, err := g.ModelToPixel(1, 1); err == nil {
+		t.Errorf("ModelToPixel() succeeded for a singular geotransform")
+	}
+}
+
+func TestPixelRect(t *testing.T) {
+	g := &Georeference{GeoTransform: [6]float64{500000, 10, 0, 5000000, 0, -10}, HasGeoTransform: true}
+	tests := []struct {
+		b    BBox
+		want image.Rectangle
+	}{
+		{BBox{500000, 4999900, 500100, 5000000}, image.Rect(0, 0, 10, 10)},
+		// Partial pixels are covered entirely.
+		{BBox{500015, 4999975, 500021, 4999995}, image.Rect(1, 0, 3, 3)},
+		// Boxes outside the raster are not clipped.
+		{BBox{499980, 5000000, 499990, 5000010}, image.Rect(-2, -1, -1, 0)},
+	}
+	for _, tt := range tests {
+		got, err := g.PixelRect(tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("PixelRect(%+v) = %v, %v, want %v", tt.b, got, err, tt.want)
+		}
+	}
+}
@@ -2,7 +2,4 @@ module github.com/echoflaresat/tiff
 
 go 1.23.2
 
-require (
-	github.com/hashicorp/golang-lru v1.0.2
-	golang.org/x/image v0.29.0
-)
+require golang.org/x/image v0.29.0
@@ -0,0 +1,339 @@
+// Package httprange implements an io.ReaderAt over HTTP Range requests, so
+// that tiles of remote (Cloud Optimized) GeoTIFFs can be decoded without
+// downloading whole files:
+//
+//	r, err := httprange.Open(ctx, "https://example.com/cog.tif", httprange.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	img, err := tiff.DecodeContext(ctx, r, tiff.Options{})
+//
+// Open fetches the first Options.PrefetchBytes of the object, which usually
+// hold the header and all directories, and records its ETag. Later reads are
+// issued as individual Range requests that are retried on transient failures
+// and rejected with ErrModified if the object changes in the meantime.
+package httprange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPrefetchBytes is the size of the initial read when
+// Options.PrefetchBytes is 0.
+const DefaultPrefetchBytes = 64 << 10
+
+// DefaultRetries is the number of retries of a failed request when
+// Options.Retries is 0.
+const DefaultRetries = 3
+
+// DefaultBackoff is the delay before the first retry when Options.Backoff
+// is 0. The delay doubles with every further retry.
+const DefaultBackoff = 100 * time.Millisecond
+
+// ErrModified is returned when the remote object no longer matches the
+// version seen by Open, as identified by its ETag or Last-Modified header.
+var ErrModified = errors.New("remote object modified")
+
+// ErrRangeUnsupported is returned by Open when the server ignores Range
+// requests and responds with the whole object.
+var ErrRangeUnsupported = errors.New("server does not support range requests")
+
+// Options configures a Reader. The zero value selects the defaults.
+type Options struct {
+	// Client issues the requests; nil selects http.DefaultClient.
+	Client *http.Client
+
+	// Header is added to every request, e.g. for authorization.
+	Header http.Header
+
+	// PrefetchBytes is the number of leading bytes fetched by Open and kept
+	// in memory. 0 selects DefaultPrefetchBytes; a negative value only
+	// fetches the first byte to learn the object size.
+	PrefetchBytes int64
+
+	// Retries is the number of times a request failing with a network error,
+	// 429 or 5xx status, or whose response body breaks off, is retried. 0 selects DefaultRetries; a negative
+	// value disables retries.
+	Retries int
+
+	// Backoff is the delay before the first retry. 0 selects DefaultBackoff.
+	Backoff time.Duration
+}
+
+// Reader reads a remote object through HTTP Range requests. It implements
+// io.ReaderAt and ReadAtContext for random access, which is safe for
+// concurrent use, and io.ReadSeeker for sequential access.
+type Reader struct {
+	url          string
+	opts         Options
+	size         int64
+	etag         string
+	lastModified string
+	prefix       []byte
+
+	mu     sync.Mutex // guards offset
+	offset int64
+}
+
+// Open fetches the first opts.PrefetchBytes of the object at url and returns
+// a Reader for it.
+func Open(ctx context.Context, url string, opts Options) (*Reader, error) {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.PrefetchBytes == 0 {
+		opts.PrefetchBytes = DefaultPrefetchBytes
+	}
+	if opts.PrefetchBytes < 0 {
+		opts.PrefetchBytes = 1
+	}
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = DefaultBackoff
+	}
+
+	r := &Reader{url: url, opts: opts}
+	err := r.do(ctx, 0, opts.PrefetchBytes, func(resp *http.Response) error {
+		if resp.StatusCode == http.StatusOK {
+			return ErrRangeUnsupported
+		}
+		first, size, err := contentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+		if first != 0 {
+			return fmt.Errorf("response starts at %d, want 0", first)
+		}
+		prefix, err := io.ReadAll(io.LimitReader(resp.Body, opts.PrefetchBytes))
+		if err != nil {
+			return err
+		}
+		r.size = size
+		r.prefix = prefix
+		r.etag = resp.Header.Get("ETag")
+		r.lastModified = resp.Header.Get("Last-Modified")
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("httprange: opening %s: %w", url, err)
+	}
+	return r, nil
+}
+
+// Size returns the size of the object in bytes.
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// ETag returns the entity tag of the object, or "" if the server sent none.
+func (r *Reader) ETag() string {
+	return r.etag
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	return r.ReadAtContext(context.Background(), p, off)
+}
+
+// ReadAtContext reads len(p) bytes at off, abandoning the request when ctx
+// is cancelled. Reads within the prefetched prefix are served from memory.
+func (r *Reader) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("httprange: negative offset %d", off)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	want := p
+	if rest := r.size - off; int64(len(want)) > rest {
+		want = want[:rest]
+	}
+	if end := off + int64(len(want)); end <= int64(len(r.prefix)) {
+		copy(want, r.prefix[off:end])
+	} else if len(want) > 0 {
+		err := r.do(ctx, off, int64(len(want)), func(resp *http.Response) error {
+			if resp.StatusCode != http.StatusPartialContent {
+				return fmt.Errorf("unexpected status %s", resp.Status)
+			}
+			if err := r.checkVersion(resp.Header); err != nil {
+				return err
+			}
+			first, _, err := contentRange(resp.Header.Get("Content-Range"))
+			if err != nil {
+				return err
+			}
+			if first != off {
+				return fmt.Errorf("response starts at %d, want %d", first, off)
+			}
+			_, err = io.ReadFull(resp.Body, want)
+			return err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("httprange: reading %d bytes at %d: %w", len(want), off, err)
+		}
+	}
+	if len(want) < len(p) {
+		return len(want), io.EOF
+	}
+	return len(p), nil
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, err := r.ReadAt(p, r.offset)
+	r.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, fmt.Errorf("httprange: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("httprange: negative position %d", offset)
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// checkVersion reports ErrModified if the validators in h differ from those
+// recorded by Open.
+func (r *Reader) checkVersion(h http.Header) error {
+	if etag := h.Get("ETag"); r.etag != "" && etag != "" && etag != r.etag {
+		return fmt.Errorf("%w: ETag %s, want %s", ErrModified, etag, r.etag)
+	}
+	if lm := h.Get("Last-Modified"); r.etag == "" && r.lastModified != "" && lm != "" && lm != r.lastModified {
+		return fmt.Errorf("%w: Last-Modified %s, want %s", ErrModified, lm, r.lastModified)
+	}
+	return nil
+}
+
+// do requests length bytes at off and passes the response to handle,
+// retrying network errors, 429 and 5xx responses with exponential backoff.
+// Errors returned by handle are retried only if the response body broke off,
+// e.g. by a connection reset; others, such as ErrModified, are returned at
+// once.
+func (r *Reader) do(ctx context.Context, off, length int64, handle func(*http.Response) error) error {
+	backoff := r.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := r.get(ctx, off, length)
+		if err == nil {
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+				err = fmt.Errorf("unexpected status %s", resp.Status)
+			} else {
+				b := &body{ReadCloser: resp.Body}
+				resp.Body = b
+				err = r.handle(resp, handle)
+				if err == nil || !b.broken(err) {
+					resp.Body.Close()
+					return err
+				}
+			}
+			resp.Body.Close()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= r.opts.Retries {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// handle checks the status of resp before passing it to fn.
+func (r *Reader) handle(resp *http.Response, fn func(*http.Response) error) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return fn(resp)
+	case http.StatusPreconditionFailed:
+		return ErrModified
+	default:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+// body records the first error of a response body other than io.EOF.
+type body struct {
+	io.ReadCloser
+	err error
+}
+
+// Read implements io.Reader.
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// broken reports whether err, returned while handling the response, stems
+// from the body breaking off rather than from its contents.
+func (b *body) broken(err error) bool {
+	return b.err != nil || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// get issues a GET request for length bytes at off. Once the ETag is known,
+// it is sent in If-Match so that the server rejects requests for a modified
+// object; weak ETags cannot be used for this and are only compared.
+func (r *Reader) get(ctx context.Context, off, length int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.opts.Header != nil {
+		req.Header = r.opts.Header.Clone()
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+length-1))
+	if r.etag != "" && !strings.HasPrefix(r.etag, "W/") {
+		req.Header.Set("If-Match", r.etag)
+	}
+	return r.opts.Client.Do(req)
+}
+
+// contentRange extracts the first byte position and the complete length
+// from a Content-Range header of the form "bytes first-last/size".
+func contentRange(header string) (first, size int64, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	bytes, total, ok2 := strings.Cut(spec, "/")
+	start, _, ok3 := strings.Cut(bytes, "-")
+	if !ok || !ok2 || !ok3 {
+		return 0, 0, fmt.Errorf("malformed Content-Range %q", header)
+	}
+	first, err = strconv.ParseInt(start, 10, 64)
+	if err != nil || first < 0 {
+		return 0, 0, fmt.Errorf("malformed Content-Range %q", header)
+	}
+	size, err = strconv.ParseInt(total, 10, 64)
+	if err != nil || size < 0 {
+		return 0, 0, fmt.Errorf("unknown object size in Content-Range %q", header)
+	}
+	return first, size, nil
+}
@@ -0,0 +1,325 @@
+package httprange
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// object is a remote object served with http.ServeContent, which supports
+// Range requests and If-Match.
+type object struct {
+	mu   sync.Mutex
+	data []byte
+	etag string
+
+	requests atomic.Int32
+
+	// fail, if set, handles a request instead; it returns false to serve
+	// the object after all.
+	fail func(w http.ResponseWriter, req *http.Request) bool
+}
+
+func (o *object) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	o.requests.Add(1)
+	o.mu.Lock()
+	data, etag, fail := o.data, o.etag, o.fail
+	o.mu.Unlock()
+	if fail != nil && fail(w, req) {
+		return
+	}
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(data))
+}
+
+// serve starts a server for o and returns its URL.
+func serve(t *testing.T, o *object) string {
+	t.Helper()
+	s := httptest.NewServer(o)
+	t.Cleanup(s.Close)
+	return s.URL
+}
+
+// testOptions keeps retries fast.
+var testOptions = Options{PrefetchBytes: 16, Backoff: time.Millisecond}
+
+func testData() []byte {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	return data
+}
+
+func TestReadAt(t *testing.T) {
+	data := testData()
+	o := &object{data: data, etag: `"v1"`}
+	r, err := Open(context.Background(), serve(t, o), testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Size() != int64(len(data)) || r.ETag() != `"v1"` {
+		t.Errorf("Size(), ETag() = %d, %q, want %d, %q", r.Size(), r.ETag(), len(data), `"v1"`)
+	}
+
+	tests := []struct {
+		off, n   int
+		want     int
+		wantEOF  bool
+		requests int32 // requests issued by the read
+	}{
+		{0, 16, 16, false, 0},
+		{4, 8, 8, false, 0},
+		{10, 100, 100, false, 1},
+		{500, 300, 300, false, 1},
+		{990, 20, 10, true, 1},
+		{1000, 1, 0, true, 0},
+	}
+	for _, tt := range tests {
+		before := o.requests.Load()
+		p := make([]byte, tt.n)
+		n, err := r.ReadAt(p, int64(tt.off))
+		if n != tt.want || (err == io.EOF) != tt.wantEOF || (err != nil && err != io.EOF) {
+			t.Errorf("ReadAt(%d bytes at %d) = %d, %v, want %d, EOF %v", tt.n, tt.off, n, err, tt.want, tt.wantEOF)
+			continue
+		}
+		if !bytes.Equal(p[:n], data[tt.off:tt.off+n]) {
+			t.Errorf("ReadAt(%d bytes at %d) returned wrong data", tt.n, tt.off)
+		}
+		if got := o.requests.Load() - before; got != tt.requests {
+			t.Errorf("ReadAt(%d bytes at %d) issued %d requests, want %d", tt.n, tt.off, got, tt.requests)
+		}
+	}
+}
+
+func TestReadSeek(t *testing.T) {
+	data := testData()
+	r, err := Open(context.Background(), serve(t, &object{data: data}), testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Seek(-100, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data[900:]) {
+		t.Errorf("ReadAll after Seek returned wrong data")
+	}
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Errorf("Seek to a negative position succeeded")
+	}
+}
+
+func TestRangeUnsupported(t *testing.T) {
+	o := &object{data: testData()}
+	o.fail = func(w http.ResponseWriter, req *http.Request) bool {
+		w.Write(o.data)
+		return true
+	}
+	if _, err := Open(context.Background(), serve(t, o), testOptions); !errors.Is(err, ErrRangeUnsupported) {
+		t.Errorf("Open() = %v, want ErrRangeUnsupported", err)
+	}
+	if n := o.requests.Load(); n != 1 {
+		t.Errorf("Open() issued %d requests, want 1", n)
+	}
+}
+
+// failFirst makes the first n requests fail with fail.
+func failFirst(n int32, fail func(w http.ResponseWriter)) func(http.ResponseWriter, *http.Request) bool {
+	var count atomic.Int32
+	return func(w http.ResponseWriter, req *http.Request) bool {
+		if count.Add(1) > n {
+			return false
+		}
+		fail(w)
+		return true
+	}
+}
+
+func TestRetry(t *testing.T) {
+	data := testData()
+	unavailable := func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }
+	// breakOff promises the whole range but closes the connection halfway.
+	breakOff := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Range", "bytes 100-199/1000")
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[100:150])
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}
+	tests := []struct {
+		name     string
+		fail     func(w http.ResponseWriter)
+		failures int32
+		retries  int
+		wantErr  bool
+	}{
+		{"503", unavailable, 2, 0, false},
+		{"503 exhausted", unavailable, 4, 0, true},
+		{"503 no retries", unavailable, 1, -1, true},
+		{"body broken off", breakOff, 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &object{data: data}
+			r, err := Open(context.Background(), serve(t, o), testOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.opts.Retries = DefaultRetries
+			if tt.retries < 0 {
+				r.opts.Retries = tt.retries
+			}
+			o.mu.Lock()
+			o.fail = failFirst(tt.failures, tt.fail)
+			o.mu.Unlock()
+
+			p := make([]byte, 100)
+			_, err = r.ReadAt(p, 100)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadAt() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(p, data[100:200]) {
+				t.Errorf("ReadAt() returned wrong data")
+			}
+		})
+	}
+}
+
+func TestModified(t *testing.T) {
+	for _, etag := range []string{`"v1"`, `W/"v1"`} {
+		t.Run(etag, func(t *testing.T) {
+			o := &object{data: testData(), etag: etag}
+			r, err := Open(context.Background(), serve(t, o), testOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			o.mu.Lock()
+			o.etag = strings.Replace(etag, "v1", "v2", 1)
+			o.mu.Unlock()
+
+			before := o.requests.Load()
+			_, err = r.ReadAt(make([]byte, 10), 100)
+			if !errors.Is(err, ErrModified) {
+				t.Fatalf("ReadAt() = %v, want ErrModified", err)
+			}
+			if n := strings.Count(err.Error(), "httprange:"); n != 1 {
+				t.Errorf("error %q has %d package prefixes, want 1", err, n)
+			}
+			if n := o.requests.Load() - before; n != 1 {
+				t.Errorf("ReadAt() issued %d requests, want 1 without retries", n)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	o := &object{data: testData()}
+	r, err := Open(context.Background(), serve(t, o), testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.mu.Lock()
+	o.fail = func(w http.ResponseWriter, req *http.Request) bool {
+		<-req.Context().Done()
+		return true
+	}
+	o.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := r.ReadAtContext(ctx, make([]byte, 10), 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ReadAtContext() = %v, want context.DeadlineExceeded", err)
+	}
+	if n := o.requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2 without retries after cancellation", n)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	var got atomic.Value
+	o := &object{data: testData(), fail: func(w http.ResponseWriter, req *http.Request) bool {
+		got.Store(req.Header.Clone())
+		return false
+	}}
+	opts := testOptions
+	opts.Header = http.Header{"X-Token": {"a", "b"}}
+	r, err := Open(context.Background(), serve(t, o), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadAt(make([]byte, 10), 100); err != nil {
+		t.Fatal(err)
+	}
+	h := got.Load().(http.Header)
+	if vs := h.Values("X-Token"); len(vs) != 2 || vs[0] != "a" || vs[1] != "b" {
+		t.Errorf("X-Token = %q, want [a b]", vs)
+	}
+	if h.Get("Range") != "bytes=100-109" {
+		t.Errorf("Range = %q", h.Get("Range"))
+	}
+	if len(opts.Header) != 1 {
+		t.Errorf("request headers leaked into Options.Header: %v", opts.Header)
+	}
+}
+
+func TestWrongRange(t *testing.T) {
+	data := testData()
+	o := &object{data: data}
+	r, err := Open(context.Background(), serve(t, o), testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.mu.Lock()
+	o.fail = func(w http.ResponseWriter, req *http.Request) bool {
+		// A broken server answering every request with the start of the object.
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-99/%d", len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[:100])
+		return true
+	}
+	o.mu.Unlock()
+
+	if _, err := r.ReadAt(make([]byte, 100), 500); err == nil {
+		t.Errorf("ReadAt() accepted a response for the wrong range")
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		first, size int64
+		wantErr     bool
+	}{
+		{"bytes 0-15/1000", 0, 1000, false},
+		{"bytes 500-599/1000", 500, 1000, false},
+		{"bytes 0-15/*", 0, 0, true},
+		{"bytes */1000", 0, 0, true},
+		{"bytes -1-15/1000", 0, 0, true},
+		{"items 0-15/1000", 0, 0, true},
+		{"", 0, 0, true},
+	}
+	for _, tt := range tests {
+		first, size, err := contentRange(tt.header)
+		if (err != nil) != tt.wantErr || (err == nil && (first != tt.first || size != tt.size)) {
+			t.Errorf("contentRange(%q) = %d, %d, %v", tt.header, first, size, err)
+		}
+	}
+}
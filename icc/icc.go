@@ -0,0 +1,137 @@
+// Package icc provides a lightweight view of ICC color profiles, as embedded
+// in TIFF files through the InterColorProfile tag (34675).
+//
+// Only the profile header and the profile description are decoded; the raw
+// profile is kept for color management systems that need the full data.
+//
+// Reference: https://www.color.org/specification/ICC.1-2022-05.pdf
+package icc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ErrNotFound is returned by FromDirectory when the image has no ICC profile.
+var ErrNotFound = errors.New("icc: no embedded profile")
+
+// headerSize is the size of the fixed ICC profile header.
+const headerSize = 128
+
+// Profile is an ICC profile with its decoded header.
+type Profile struct {
+	// Raw holds the complete profile as embedded in the file.
+	Raw []byte
+
+	Size            uint32
+	CMM             string // preferred color management module signature
+	Version         string // profile version, e.g. "4.3.0"
+	Class           string // device class signature, e.g. "mntr", "prtr", "spac"
+	ColorSpace      string // data color space signature, e.g. "RGB ", "CMYK", "GRAY"
+	PCS             string // profile connection space, "XYZ " or "Lab "
+	Created         time.Time
+	Platform        string
+	Manufacturer    string
+	Model           string
+	RenderingIntent uint32
+
+	// Description is the profile description ('desc' tag), e.g. "sRGB IEC61966-2.1".
+	Description string
+}
+
+// FromDirectory parses the ICC profile embedded in the image described by d.
+func FromDirectory(d *ifd.Directory) (*Profile, error) {
+	raw, ok := d.Bytes(tifftag.ICCProfile)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return Parse(raw)
+}
+
+// Parse decodes the header and description of an ICC profile.
+func Parse(raw []byte) (*Profile, error) {
+	if len(raw) < headerSize+4 || string(raw[36:40]) != "acsp" {
+		return nil, errors.New("icc: invalid profile header")
+	}
+	be := binary.BigEndian
+	p := &Profile{
+		Raw:             raw,
+		Size:            be.Uint32(raw[0:4]),
+		CMM:             signature(raw[4:8]),
+		Version:         fmt.Sprintf("%d.%d.%d", raw[8], raw[9]>>4, raw[9]&0x0f),
+		Class:           signature(raw[12:16]),
+		ColorSpace:      signature(raw[16:20]),
+		PCS:             signature(raw[20:24]),
+		Platform:        signature(raw[40:44]),
+		Manufacturer:    signature(raw[48:52]),
+		Model:           signature(raw[52:56]),
+		RenderingIntent: be.Uint32(raw[64:68]),
+	}
+	u16 := func(i int) int { return int(be.Uint16(raw[24+2*i:])) }
+	if u16(0) != 0 {
+		p.Created = time.Date(u16(0), time.Month(u16(1)), u16(2), u16(3), u16(4), u16(5), 0, time.UTC)
+	}
+	p.Description = description(raw)
+	return p, nil
+}
+
+// signature returns a four-byte signature as a string, or "" if it is all zeros.
+func signature(b []byte) string {
+	if binary.BigEndian.Uint32(b) == 0 {
+		return ""
+	}
+	return string(b)
+}
+
+// description decodes the 'desc' tag, which is a textDescriptionType in
+// version 2 profiles and a multiLocalizedUnicodeType in version 4 profiles.
+// It returns "" if the tag is absent or malformed.
+func description(raw []byte) string {
+	be := binary.BigEndian
+	count := int(be.Uint32(raw[headerSize:]))
+	for i := 0; i < count; i++ {
+		entry := headerSize + 4 + 12*i
+		if entry+12 > len(raw) {
+			return ""
+		}
+		if string(raw[entry:entry+4]) != "desc" {
+			continue
+		}
+		offset, size := int(be.Uint32(raw[entry+4:])), int(be.Uint32(raw[entry+8:]))
+		if offset < 0 || size < 12 || offset+size > len(raw) {
+			return ""
+		}
+		data := raw[offset : offset+size]
+		switch string(data[0:4]) {
+		case "desc":
+			n := int(be.Uint32(data[8:12]))
+			if 12+n > len(data) {
+				return ""
+			}
+			return strings.TrimRight(string(data[12:12+n]), "\x00")
+		case "mluc":
+			if len(data) < 28 {
+				return ""
+			}
+			// Use the first record; its offset is relative to the tag start.
+			length, start := int(be.Uint32(data[20:24])), int(be.Uint32(data[24:28]))
+			if start+length > len(data) {
+				return ""
+			}
+			units := make([]uint16, length/2)
+			for j := range units {
+				units[j] = be.Uint16(data[start+2*j:])
+			}
+			return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+		}
+		return ""
+	}
+	return ""
+}
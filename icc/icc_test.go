@@ -0,0 +1,123 @@
+package icc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// profile returns an ICC profile of the given version whose only tag is the
+// description desc, encoded as required by that version.
+func profile(major byte, desc string) []byte {
+	be := binary.BigEndian
+	var data []byte
+	if major < 4 {
+		data = append([]byte("desc\x00\x00\x00\x00"), be.AppendUint32(nil, uint32(len(desc)+1))...)
+		data = append(data, desc...)
+		data = append(data, 0)
+	} else {
+		units := utf16.Encode([]rune(desc))
+		data = append([]byte("mluc\x00\x00\x00\x00"), be.AppendUint32(nil, 1)...)
+		data = be.AppendUint32(data, 12)
+		data = append(data, "enUS"...)
+		data = be.AppendUint32(data, uint32(2*len(units)))
+		data = be.AppendUint32(data, 28)
+		for _, u := range units {
+			data = be.AppendUint16(data, u)
+		}
+	}
+
+	raw := make([]byte, headerSize)
+	copy(raw[4:], "lcms")
+	raw[8], raw[9] = major, 0x30
+	copy(raw[12:], "mntr")
+	copy(raw[16:], "RGB ")
+	copy(raw[20:], "XYZ ")
+	for i, v := range []uint16{2024, 5, 6, 7, 8, 9} {
+		be.PutUint16(raw[24+2*i:], v)
+	}
+	copy(raw[36:], "acsp")
+	copy(raw[40:], "APPL")
+	be.PutUint32(raw[64:], 1)
+
+	raw = be.AppendUint32(raw, 1)
+	raw = append(raw, "desc"...)
+	raw = be.AppendUint32(raw, uint32(headerSize+4+12))
+	raw = be.AppendUint32(raw, uint32(len(data)))
+	raw = append(raw, data...)
+	be.PutUint32(raw[0:], uint32(len(raw)))
+	return raw
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		major   byte
+		version string
+	}{
+		{2, "2.3.0"},
+		{4, "4.3.0"},
+	} {
+		raw := profile(tt.major, "sRGB IEC61966-2.1")
+		p, err := Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Version != tt.version || p.Description != "sRGB IEC61966-2.1" {
+			t.Errorf("v%d: Version %q, Description %q", tt.major, p.Version, p.Description)
+		}
+		if p.Size != uint32(len(raw)) || p.CMM != "lcms" || p.Class != "mntr" || p.ColorSpace != "RGB " || p.PCS != "XYZ " {
+			t.Errorf("v%d: header = %+v", tt.major, p)
+		}
+		if p.Platform != "APPL" || p.Manufacturer != "" || p.RenderingIntent != 1 {
+			t.Errorf("v%d: Platform %q, Manufacturer %q, RenderingIntent %d", tt.major, p.Platform, p.Manufacturer, p.RenderingIntent)
+		}
+		if want := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC); !p.Created.Equal(want) {
+			t.Errorf("v%d: Created = %v", tt.major, p.Created)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	raw := profile(2, "x")
+	raw[36] = 'x'
+	if _, err := Parse(raw); err == nil {
+		t.Errorf("Parse() accepted a profile without acsp signature")
+	}
+	if _, err := Parse(raw[:64]); err == nil {
+		t.Errorf("Parse() accepted a truncated header")
+	}
+
+	// A corrupt description leaves the header usable.
+	raw = profile(2, "x")
+	binary.BigEndian.PutUint32(raw[headerSize+8:], 1<<20)
+	if p, err := Parse(raw); err != nil || p.Description != "" {
+		t.Errorf("Parse() = %+v, %v", p, err)
+	}
+}
+
+func TestFromDirectory(t *testing.T) {
+	for _, entries := range [][]tifftest.Entry{
+		tifftest.Gray(1, 1),
+		append(tifftest.Gray(1, 1), tifftest.Undefined(tifftag.ICCProfile, profile(2, "Gray"))),
+	} {
+		dirs, err := ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: entries})), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := FromDirectory(dirs[0])
+		if dirs[0].Has(tifftag.ICCProfile) {
+			if err != nil || p.Description != "Gray" {
+				t.Errorf("FromDirectory() = %+v, %v", p, err)
+			}
+		} else if !errors.Is(err, ErrNotFound) {
+			t.Errorf("FromDirectory() = %v, want ErrNotFound", err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package ifd
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// Directory is a parsed TIFF image file directory (IFD).
+//
+// It holds every entry of the directory, including tags this module does not
+// interpret, and offers typed lookups on top of the raw values.
+type Directory struct {
+	// ByteOrder is the byte order of the file the directory was read from.
+	ByteOrder binary.ByteOrder
+
+	// BigTIFF is set if the directory uses the BigTIFF layout.
+	BigTIFF bool
+
+	// Offset is the file offset of the directory.
+	Offset int64
+
+	// Next is the offset of the next directory in the chain, or 0 for the last one.
+	Next int64
+
+	// Entries holds the directory entries in file order.
+	Entries []Entry
+}
+
+// Entry returns the entry for tag, including entries whose value could not
+// be read (see Entry.Err).
+func (d *Directory) Entry(tag tifftag.Tag) (Entry, bool) {
+	for _, e := range d.Entries {
+		if e.Tag == tag {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// value returns the entry for tag if its value could be read.
+func (d *Directory) value(tag tifftag.Tag) (Entry, bool) {
+	e, ok := d.Entry(tag)
+	return e, ok && e.Err == nil
+}
+
+// Err returns the errors of all values that could not be read, or nil if
+// every value was read.
+func (d *Directory) Err() error {
+	var errs []error
+	for _, e := range d.Entries {
+		if e.Err != nil {
+			errs = append(errs, e.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Has reports whether the directory contains tag.
+func (d *Directory) Has(tag tifftag.Tag) bool {
+	_, ok := d.Entry(tag)
+	return ok
+}
+
+// Tags returns the tags present in the directory in ascending order.
+func (d *Directory) Tags() []tifftag.Tag {
+	tags := make([]tifftag.Tag, len(d.Entries))
+	for i, e := range d.Entries {
+		tags[i] = e.Tag
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	return tags
+}
+
+// Bytes returns the raw value bytes of tag in the byte order of the file.
+// It works for every entry, including unknown tags and field types.
+func (d *Directory) Bytes(tag tifftag.Tag) ([]byte, bool) {
+	e, ok := d.value(tag)
+	if !ok {
+		return nil, false
+	}
+	return e.Raw, true
+}
+
+// Uint returns the first value of an unsigned integer tag.
+func (d *Directory) Uint(tag tifftag.Tag) (uint64, bool) {
+	vs, ok := d.Uints(tag)
+	if !ok || len(vs) == 0 {
+		return 0, false
+	}
+	return vs[0], true
+}
+
+// Uints returns the values of an unsigned integer tag
+// (BYTE, SHORT, LONG, LONG8, IFD or IFD8).
+func (d *Directory) Uints(tag tifftag.Tag) ([]uint64, bool) {
+	e, ok := d.value(tag)
+	if !ok {
+		return nil, false
+	}
+	return e.Uints()
+}
+
+// Int returns the first value of an integer tag, signed or unsigned.
+func (d *Directory) Int(tag tifftag.Tag) (int64, bool) {
+	vs, ok := d.Ints(tag)
+	if !ok || len(vs) == 0 {
+		return 0, false
+	}
+	return vs[0], true
+}
+
+// Ints returns the values of an integer tag, signed or unsigned.
+func (d *Directory) Ints(tag tifftag.Tag) ([]int64, bool) {
+	e, ok := d.value(tag)
+	if !ok {
+		return nil, false
+	}
+	return e.Ints()
+}
+
+// Float returns the first value of a numeric tag as float64.
+func (d *Directory) Float(tag tifftag.Tag) (float64, bool) {
+	vs, ok := d.Floats(tag)
+	if !ok || len(vs) == 0 {
+		return 0, false
+	}
+	return vs[0], true
+}
+
+// Floats returns the values of a numeric tag as float64, converting
+// integers and rationals.
+func (d *Directory) Floats(tag tifftag.Tag) ([]float64, bool) {
+	e, ok := d.value(tag)
+	if !ok {
+		return nil, false
+	}
+	return e.Floats()
+}
+
+// Rational returns the first value of a RATIONAL or SRATIONAL tag.
+func (d *Directory) Rational(tag tifftag.Tag) (Rational, bool) {
+	vs, ok := d.Rationals(tag)
+	if !ok || len(vs) == 0 {
+		return Rational{}, false
+	}
+	return vs[0], true
+}
+
+// Rationals returns the values of a RATIONAL or SRATIONAL tag.
+func (d *Directory) Rationals(tag tifftag.Tag) ([]Rational, bool) {
+	e, ok := d.value(tag)
+	if !ok {
+		return nil, false
+	}
+	return e.Rationals()
+}
+
+// ASCII returns the value of an ASCII tag up to its first NUL,
+// e.g. Software, DateTime, Artist or ImageDescription.
+func (d *Directory) ASCII(tag tifftag.Tag) (string, bool) {
+	e, ok := d.value(tag)
+	if !ok {
+		return "", false
+	}
+	ss, ok := e.ASCII()
+	if !ok || len(ss) == 0 {
+		return "", false
+	}
+	return ss[0], true
+}
+
+// Header returns the file header fields needed to read sub-directories
+// referenced by d, such as the EXIF IFD.
+func (d *Directory) Header() Header {
+	return Header{ByteOrder: d.ByteOrder, BigTIFF: d.BigTIFF}
+}
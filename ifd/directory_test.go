@@ -0,0 +1,103 @@
+package ifd_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// lookups returns a directory with one entry of every kind checked below.
+func lookups(order binary.ByteOrder, big bool) *ifd.Directory {
+	data := tifftest.File{ByteOrder: order, BigTIFF: big, IFDs: []tifftest.IFD{{
+		Entries: []tifftest.Entry{
+			tifftest.Short(tifftag.ImageWidth, 640),
+			tifftest.Long(tifftag.StripOffsets, 8, 1<<20, 1<<32-1),
+			tifftest.ASCII(tifftag.Software, "tiff\x00second"),
+			tifftest.Rational(tifftag.XResolution, 300, 2),
+			tifftest.Double(tifftag.ModelPixelScale, 0.5, -2),
+			{Tag: 50000, Type: fieldtype.SShort, Values: []uint64{uint64(0xffff), 7}},
+			{Tag: 50001, Type: fieldtype.SRational, Values: []uint64{uint64(0xffffffff), 4}},
+			{Tag: 50002, Type: 99, Raw: []byte{1, 2, 3, 4}},
+		},
+	}}}.Bytes()
+	dirs, err := ifd.ReadAll(bytes.NewReader(data), 0)
+	if err != nil {
+		panic(err)
+	}
+	return dirs[0]
+}
+
+func TestDirectoryLookups(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		order binary.ByteOrder
+		big   bool
+	}{
+		{"little-endian", binary.LittleEndian, false},
+		{"big-endian", binary.BigEndian, false},
+		{"BigTIFF", binary.LittleEndian, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := lookups(tt.order, tt.big)
+			if d.BigTIFF != tt.big || d.ByteOrder != tt.order {
+				t.Errorf("layout = %v %v, want %v %v", d.ByteOrder, d.BigTIFF, tt.order, tt.big)
+			}
+			if v, ok := d.Uint(tifftag.ImageWidth); !ok || v != 640 {
+				t.Errorf("Uint(ImageWidth) = %d, %v", v, ok)
+			}
+			if vs, ok := d.Uints(tifftag.StripOffsets); !ok || len(vs) != 3 || vs[2] != 1<<32-1 {
+				t.Errorf("Uints(StripOffsets) = %v, %v", vs, ok)
+			}
+			if s, ok := d.ASCII(tifftag.Software); !ok || s != "tiff" {
+				t.Errorf("ASCII(Software) = %q, %v", s, ok)
+			}
+			if e, _ := d.Entry(tifftag.Software); len(e.Raw) != 12 {
+				t.Errorf("Software entry holds %d bytes, want 12", len(e.Raw))
+			} else if ss, _ := e.ASCII(); len(ss) != 2 || ss[1] != "second" {
+				t.Errorf("Entry.ASCII() = %q", ss)
+			}
+			if r, ok := d.Rational(tifftag.XResolution); !ok || r != (ifd.Rational{Num: 300, Den: 2}) || r.Float64() != 150 {
+				t.Errorf("Rational(XResolution) = %v, %v", r, ok)
+			}
+			if fs, ok := d.Floats(tifftag.ModelPixelScale); !ok || len(fs) != 2 || fs[0] != 0.5 || fs[1] != -2 {
+				t.Errorf("Floats(ModelPixelScale) = %v, %v", fs, ok)
+			}
+			if f, ok := d.Float(tifftag.XResolution); !ok || f != 150 {
+				t.Errorf("Float(XResolution) = %v, %v", f, ok)
+			}
+			if is, ok := d.Ints(50000); !ok || len(is) != 2 || is[0] != -1 || is[1] != 7 {
+				t.Errorf("Ints(SSHORT) = %v, %v", is, ok)
+			}
+			if _, ok := d.Uints(50000); ok {
+				t.Errorf("Uints(SSHORT) succeeded")
+			}
+			if r, ok := d.Rational(50001); !ok || r != (ifd.Rational{Num: -1, Den: 4}) {
+				t.Errorf("Rational(SRATIONAL) = %v, %v", r, ok)
+			}
+			if b, ok := d.Bytes(50002); !ok || !bytes.HasPrefix(b, []byte{1, 2, 3, 4}) {
+				t.Errorf("Bytes(unknown type) = %v, %v", b, ok)
+			}
+			if _, ok := d.Uint(tifftag.Artist); ok || d.Has(tifftag.Artist) {
+				t.Errorf("absent tag found")
+			}
+			if tags := d.Tags(); len(tags) != 8 || tags[0] != tifftag.ImageWidth {
+				t.Errorf("Tags() = %v", tags)
+			}
+			if err := d.Err(); err != nil {
+				t.Errorf("Err() = %v", err)
+			}
+		})
+	}
+}
+
+func TestRationalZeroDenominator(t *testing.T) {
+	if f := (ifd.Rational{Num: 1}).Float64(); !math.IsNaN(f) {
+		t.Errorf("Float64() = %v, want NaN", f)
+	}
+}
@@ -0,0 +1,187 @@
+package ifd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// Entry is a single tag of a directory together with its raw value.
+type Entry struct {
+	Tag   tifftag.Tag
+	Type  fieldtype.Type
+	Count uint64
+
+	// Raw holds the Count values in the byte order of the file.
+	// Entries with an unknown field type keep their bytes undecoded.
+	Raw []byte
+
+	// Err is set if the value could not be read, e.g. because its offset
+	// lies beyond the end of the file; Raw is nil then.
+	Err error
+
+	order binary.ByteOrder
+}
+
+// Rational is a TIFF RATIONAL or SRATIONAL value.
+type Rational struct {
+	Num, Den int64
+}
+
+// Float64 returns the value of r, or NaN if the denominator is zero.
+func (r Rational) Float64() float64 {
+	if r.Den == 0 {
+		return math.NaN()
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// Uints returns the values of an unsigned integer entry
+// (BYTE, SHORT, LONG, LONG8, IFD or IFD8).
+func (e Entry) Uints() ([]uint64, bool) {
+	size := e.Type.Size()
+	switch e.Type {
+	case fieldtype.Byte, fieldtype.Short, fieldtype.Long, fieldtype.IFD, fieldtype.Long8, fieldtype.IFD8:
+	default:
+		return nil, false
+	}
+	out := make([]uint64, e.values())
+	for i := range out {
+		out[i] = e.uint(e.Raw[i*size:], size)
+	}
+	return out, true
+}
+
+// Ints returns the values of any integer entry, signed or unsigned.
+func (e Entry) Ints() ([]int64, bool) {
+	size := e.Type.Size()
+	out := make([]int64, e.values())
+	switch e.Type {
+	case fieldtype.Byte, fieldtype.Short, fieldtype.Long, fieldtype.IFD, fieldtype.Long8, fieldtype.IFD8:
+		for i := range out {
+			out[i] = int64(e.uint(e.Raw[i*size:], size))
+		}
+	case fieldtype.SByte:
+		for i := range out {
+			out[i] = int64(int8(e.Raw[i]))
+		}
+	case fieldtype.SShort:
+		for i := range out {
+			out[i] = int64(int16(e.order.Uint16(e.Raw[i*2:])))
+		}
+	case fieldtype.SLong:
+		for i := range out {
+			out[i] = int64(int32(e.order.Uint32(e.Raw[i*4:])))
+		}
+	case fieldtype.SLong8:
+		for i := range out {
+			out[i] = int64(e.order.Uint64(e.Raw[i*8:]))
+		}
+	default:
+		return nil, false
+	}
+	return out, true
+}
+
+// Rationals returns the values of a RATIONAL or SRATIONAL entry.
+func (e Entry) Rationals() ([]Rational, bool) {
+	out := make([]Rational, e.values())
+	switch e.Type {
+	case fieldtype.Rational:
+		for i := range out {
+			out[i] = Rational{
+				Num: int64(e.order.Uint32(e.Raw[i*8:])),
+				Den: int64(e.order.Uint32(e.Raw[i*8+4:])),
+			}
+		}
+	case fieldtype.SRational:
+		for i := range out {
+			out[i] = Rational{
+				Num: int64(int32(e.order.Uint32(e.Raw[i*8:]))),
+				Den: int64(int32(e.order.Uint32(e.Raw[i*8+4:]))),
+			}
+		}
+	default:
+		return nil, false
+	}
+	return out, true
+}
+
+// Floats returns the values of any numeric entry as float64,
+// converting integers and rationals.
+func (e Entry) Floats() ([]float64, bool) {
+	switch e.Type {
+	case fieldtype.Float:
+		out := make([]float64, e.values())
+		for i := range out {
+			out[i] = float64(math.Float32frombits(e.order.Uint32(e.Raw[i*4:])))
+		}
+		return out, true
+	case fieldtype.Double:
+		out := make([]float64, e.values())
+		for i := range out {
+			out[i] = math.Float64frombits(e.order.Uint64(e.Raw[i*8:]))
+		}
+		return out, true
+	}
+	if rs, ok := e.Rationals(); ok {
+		out := make([]float64, len(rs))
+		for i, r := range rs {
+			out[i] = r.Float64()
+		}
+		return out, true
+	}
+	if is, ok := e.Ints(); ok {
+		out := make([]float64, len(is))
+		for i, v := range is {
+			out[i] = float64(v)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// ASCII returns the strings of an ASCII entry.
+// Multiple NUL-separated strings are returned separately; a missing final NUL is tolerated.
+func (e Entry) ASCII() ([]string, bool) {
+	if e.Type != fieldtype.ASCII {
+		return nil, false
+	}
+	raw := bytes.TrimSuffix(e.Raw, []byte{0})
+	var out []string
+	for _, s := range bytes.Split(raw, []byte{0}) {
+		out = append(out, string(s))
+	}
+	return out, true
+}
+
+// values returns the number of complete values held in Raw, which may be
+// fewer than Count for truncated entries.
+func (e Entry) values() int {
+	size := e.Type.Size()
+	if size == 0 {
+		return 0
+	}
+	n := len(e.Raw) / size
+	if uint64(n) > e.Count {
+		n = int(e.Count)
+	}
+	return n
+}
+
+// uint decodes an unsigned integer of the given size from b.
+func (e Entry) uint(b []byte, size int) uint64 {
+	switch size {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(e.order.Uint16(b))
+	case 4:
+		return uint64(e.order.Uint32(b))
+	default:
+		return e.order.Uint64(b)
+	}
+}
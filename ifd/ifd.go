@@ -0,0 +1,347 @@
+// Package ifd parses TIFF image file directories (IFDs) into a generic,
+// tag-agnostic representation.
+//
+// Every entry of a directory is kept, including private and unknown tags,
+// so callers can read metadata such as Software, DateTime, Artist,
+// ImageDescription, resolution or any custom tag through typed lookups:
+//
+//	dirs, err := ifd.ReadAll(f, 0)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	software, _ := dirs[0].ASCII(tifftag.Software)
+//	xres, _ := dirs[0].Rational(tifftag.XResolution)
+package ifd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ErrInvalidHeader is returned when the TIFF header is missing, malformed,
+// or not conforming to the expected structure (e.g., wrong magic number).
+var ErrInvalidHeader = errors.New("invalid TIFF header")
+
+// ErrInvalidDirectory is returned by Directory.Validate for directories that
+// violate the TIFF specification.
+var ErrInvalidDirectory = errors.New("invalid TIFF directory")
+
+// maxDirectories bounds the number of IFDs followed in a single file,
+// protecting against cyclic or corrupt IFD chains.
+const maxDirectories = 4096
+
+// maxValueSize bounds the size of a single entry value, protecting against
+// corrupt counts that would otherwise trigger huge allocations. Values are
+// also checked against the size of the reader when it is known.
+const maxValueSize = 1 << 26
+
+// Values stored outside their entries are read together if at most
+// valueGap bytes separate them and the read stays within maxValueRead bytes,
+// which saves round trips on remote readers.
+const (
+	valueGap     = 4 << 10
+	maxValueRead = 1 << 20
+)
+
+// Header is the fixed-size header at the start of a TIFF file.
+type Header struct {
+	// ByteOrder is little-endian for "II" files and big-endian for "MM" files.
+	ByteOrder binary.ByteOrder
+
+	// BigTIFF is set for BigTIFF files (version 43), whose directories use
+	// 64-bit counts and offsets.
+	BigTIFF bool
+
+	// FirstIFD is the offset of the first image file directory.
+	FirstIFD int64
+}
+
+// ReadHeader reads and validates the TIFF or BigTIFF header at the start of r.
+func ReadHeader(r io.ReaderAt) (Header, error) {
+	header, err := readAt(r, 0, 8)
+	if err != nil {
+		return Header{}, err
+	}
+
+	var bo binary.ByteOrder
+	switch string(header[0:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return Header{}, ErrInvalidHeader
+	}
+	switch bo.Uint16(header[2:4]) {
+	case 42:
+		return Header{ByteOrder: bo, FirstIFD: int64(bo.Uint32(header[4:8]))}, nil
+	case 43:
+		// BigTIFF: offset byte size (always 8), a reserved zero and a 64-bit offset.
+		if bo.Uint16(header[4:6]) != 8 || bo.Uint16(header[6:8]) != 0 {
+			return Header{}, ErrInvalidHeader
+		}
+		offset, err := readAt(r, 8, 8)
+		if err != nil {
+			return Header{}, err
+		}
+		return Header{ByteOrder: bo, BigTIFF: true, FirstIFD: int64(bo.Uint64(offset))}, nil
+	default:
+		return Header{}, ErrInvalidHeader
+	}
+}
+
+// ReadAll reads the TIFF header and follows the IFD chain, returning the
+// directories in file order. At most limit directories are read; a limit <= 0
+// reads the whole chain.
+func ReadAll(r io.ReaderAt, limit int) ([]*Directory, error) {
+	c, err := NewChain(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []*Directory
+	for limit <= 0 || len(dirs) < limit {
+		d, err := c.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, d)
+	}
+	if len(dirs) == 0 {
+		return nil, ErrInvalidHeader
+	}
+	return dirs, nil
+}
+
+// Chain reads the directories of a file one at a time in file order, so
+// that callers can stop at the directory they need and decide how to treat
+// errors in the directories following it.
+type Chain struct {
+	r       io.ReaderAt
+	header  Header
+	next    int64
+	visited map[int64]bool
+}
+
+// NewChain reads the TIFF header of r and returns a Chain positioned at the
+// first directory.
+func NewChain(r io.ReaderAt) (*Chain, error) {
+	h, err := ReadHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Chain{r: r, header: h, next: h.FirstIFD, visited: map[int64]bool{}}, nil
+}
+
+// Header returns the header of the file.
+func (c *Chain) Header() Header {
+	return c.header
+}
+
+// Next reads the next directory of the chain. It returns io.EOF after the
+// last directory. A directory that cannot be read ends the chain: Next
+// returns its error once and io.EOF afterwards.
+func (c *Chain) Next() (*Directory, error) {
+	offset := c.next
+	if offset == 0 {
+		return nil, io.EOF
+	}
+	c.next = 0
+	if c.visited[offset] || len(c.visited) >= maxDirectories {
+		return nil, fmt.Errorf("%w: cyclic or oversized IFD chain", ErrInvalidHeader)
+	}
+	c.visited[offset] = true
+
+	d, err := ReadDirectory(c.r, c.header, offset)
+	if err != nil {
+		return nil, err
+	}
+	c.next = d.Next
+	return d, nil
+}
+
+// ReadDirectory reads the single directory at offset, including the values
+// of all its entries. It is also used for sub-directories such as the EXIF IFD,
+// with the header of the file they belong to.
+//
+// Values stored outside the directory are read with as few reads as
+// possible. A value that cannot be read does not fail the directory: its
+// entry keeps the error in Err and typed lookups treat the tag as absent.
+func ReadDirectory(r io.ReaderAt, h Header, offset int64) (*Directory, error) {
+	// Classic TIFF uses 16-bit entry counts, 12-byte entries and 32-bit
+	// offsets; BigTIFF uses 64-bit counts, 20-byte entries and 64-bit offsets.
+	countSize, entrySize, offsetSize := 2, 12, 4
+	if h.BigTIFF {
+		countSize, entrySize, offsetSize = 8, 20, 8
+	}
+	order := h.ByteOrder
+
+	countRaw, err := readAt(r, offset, countSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading IFD at %d: %w", offset, err)
+	}
+	n := readUint(order, countRaw)
+	if n > maxValueSize/uint64(entrySize) {
+		return nil, fmt.Errorf("reading IFD at %d: too many entries: %d", offset, n)
+	}
+	numEntries := int(n)
+	raw, err := readAt(r, offset+int64(countSize), numEntries*entrySize+offsetSize)
+	if err != nil {
+		return nil, fmt.Errorf("reading IFD at %d: %w", offset, err)
+	}
+
+	d := &Directory{
+		ByteOrder: order,
+		BigTIFF:   h.BigTIFF,
+		Offset:    offset,
+		Next:      int64(readUint(order, raw[numEntries*entrySize:])),
+		Entries:   make([]Entry, 0, numEntries),
+	}
+	var values []value
+	for i := 0; i < numEntries; i++ {
+		field := raw[i*entrySize : (i+1)*entrySize]
+		e := Entry{
+			Tag:   tifftag.Tag(order.Uint16(field[0:2])),
+			Type:  fieldtype.Type(order.Uint16(field[2:4])),
+			Count: readUint(order, field[4:4+offsetSize]),
+			order: order,
+		}
+		v, err := valueOf(order, e, field[4+offsetSize:])
+		switch {
+		case err != nil:
+			e.Err = fmt.Errorf("reading %s: %w", e.Tag, err)
+		case v.length == 0:
+			e.Raw = v.inline
+		default:
+			v.entry = len(d.Entries)
+			values = append(values, v)
+		}
+		d.Entries = append(d.Entries, e)
+	}
+	readValues(r, d.Entries, values)
+	return d, nil
+}
+
+// value locates the value bytes of an entry: inline in the entry itself, or
+// length bytes at offset.
+type value struct {
+	entry  int // index of the entry in its directory
+	inline []byte
+	offset int64
+	length int
+}
+
+// end returns the offset just past the value.
+func (v value) end() int64 {
+	return v.offset + int64(v.length)
+}
+
+// valueOf locates the value bytes of e. Values that fit into the value field
+// of the entry (4 bytes, or 8 in BigTIFF) are stored inline; larger ones are
+// stored at the offset the field holds. Unknown field types yield the raw
+// value field.
+func valueOf(order binary.ByteOrder, e Entry, field []byte) (value, error) {
+	size := uint64(e.Type.Size())
+	if size == 0 {
+		return value{inline: append([]byte(nil), field...)}, nil
+	}
+	if e.Count > maxValueSize/size {
+		return value{}, fmt.Errorf("value too large: %d × %s", e.Count, e.Type)
+	}
+	n := int(e.Count * size)
+	if n <= len(field) {
+		return value{inline: append([]byte(nil), field[:n]...)}, nil
+	}
+	return value{offset: int64(readUint(order, field)), length: n}, nil
+}
+
+// readValues reads the given out-of-line values into the Raw fields of
+// entries, merging nearby values into single reads. Values are read again
+// individually if a merged read fails, so that an unreadable value only
+// affects its own entry.
+func readValues(r io.ReaderAt, entries []Entry, values []value) {
+	sort.Slice(values, func(i, j int) bool { return values[i].offset < values[j].offset })
+	for len(values) > 0 {
+		start, end, n := values[0].offset, values[0].end(), 1
+		for ; n < len(values); n++ {
+			v := values[n]
+			if v.offset-end > valueGap || max(end, v.end())-start > maxValueRead {
+				break
+			}
+			end = max(end, v.end())
+		}
+		group := values[:n]
+		values = values[n:]
+
+		if n > 1 {
+			if buf, err := readAt(r, start, int(end-start)); err == nil {
+				for _, v := range group {
+					entries[v.entry].Raw = buf[v.offset-start : v.end()-start : v.end()-start]
+				}
+				continue
+			}
+		}
+		for _, v := range group {
+			e := &entries[v.entry]
+			if e.Raw, e.Err = readAt(r, v.offset, v.length); e.Err != nil {
+				e.Err = fmt.Errorf("reading %s at %d: %w", e.Tag, v.offset, e.Err)
+			}
+		}
+	}
+}
+
+// readUint decodes a 16-, 32- or 64-bit unsigned integer from b,
+// depending on its length.
+func readUint(order binary.ByteOrder, b []byte) uint64 {
+	switch len(b) {
+	case 2:
+		return uint64(order.Uint16(b))
+	case 4:
+		return uint64(order.Uint32(b))
+	default:
+		return order.Uint64(b)
+	}
+}
+
+// readAt reads exactly size bytes at offset. Reads extending past the end
+// of r fail before anything is allocated if the size of r is known.
+func readAt(r io.ReaderAt, offset int64, size int) ([]byte, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("negative offset %d", offset)
+	}
+	if end := readerSize(r); end >= 0 && int64(size) > end-offset {
+		return nil, io.ErrUnexpectedEOF
+	}
+	buf := make([]byte, size)
+	n, err := r.ReadAt(buf, offset)
+	if n == size {
+		return buf, nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return nil, err
+}
+
+// readerSize returns the size of r, or -1 if it cannot be determined.
+func readerSize(r io.ReaderAt) int64 {
+	switch r := r.(type) {
+	case interface{ Size() int64 }:
+		return r.Size()
+	case *os.File:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
@@ -0,0 +1,201 @@
+package ifd_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestChain(t *testing.T) {
+	second := tifftest.IFD{Entries: tifftest.Gray(2, 2), Blocks: [][]byte{{0, 0, 0, 0}}, Next: 1 << 20}
+	data := tifftest.Build(
+		tifftest.IFD{Entries: tifftest.Gray(4, 4), Blocks: [][]byte{make([]byte, 16)}},
+		second,
+	)
+
+	c, err := ifd.NewChain(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []uint64{4, 2} {
+		d, err := c.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w, _ := d.Uint(tifftag.ImageWidth); w != want {
+			t.Errorf("ImageWidth = %d, want %d", w, want)
+		}
+	}
+	if _, err := c.Next(); err == nil || err == io.EOF {
+		t.Errorf("Next() past a corrupt offset = %v, want read error", err)
+	}
+	if _, err := c.Next(); err != io.EOF {
+		t.Errorf("Next() after error = %v, want io.EOF", err)
+	}
+
+	if _, err := ifd.ReadAll(bytes.NewReader(data), 0); err == nil {
+		t.Errorf("ReadAll() succeeded on a corrupt chain")
+	}
+	dirs, err := ifd.ReadAll(bytes.NewReader(data), 2)
+	if err != nil || len(dirs) != 2 {
+		t.Errorf("ReadAll(limit 2) = %d directories, %v; want 2, nil", len(dirs), err)
+	}
+}
+
+func TestChainCycle(t *testing.T) {
+	d := tifftest.IFD{Entries: tifftest.Gray(1, 1), Blocks: [][]byte{{0}}}
+	d.Next = uint64(binary.LittleEndian.Uint32(tifftest.Build(d)[4:]))
+	data := tifftest.Build(d)
+
+	_, err := ifd.ReadAll(bytes.NewReader(data), 0)
+	if !errors.Is(err, ifd.ErrInvalidHeader) {
+		t.Errorf("ReadAll() of a cyclic chain = %v, want ErrInvalidHeader", err)
+	}
+}
+
+// countingReader counts the reads issued to a reader.
+type countingReader struct {
+	r     *bytes.Reader
+	reads int
+}
+
+func (c *countingReader) ReadAt(p []byte, off int64) (int, error) {
+	c.reads++
+	return c.r.ReadAt(p, off)
+}
+
+func TestReadDirectoryCoalescesValues(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{Entries: []tifftest.Entry{
+		tifftest.ASCII(tifftag.ImageDescription, "a description"),
+		tifftest.ASCII(tifftag.Software, "some software"),
+		tifftest.ASCII(tifftag.Artist, "an artist"),
+		tifftest.Rational(tifftag.XResolution, 72, 1),
+		tifftest.Rational(tifftag.YResolution, 72, 1),
+	}})
+	r := &countingReader{r: bytes.NewReader(data)}
+	h, err := ifd.ReadHeader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.reads = 0
+	d, err := ifd.ReadDirectory(r, h, h.FirstIFD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The entry count, the entries and one read for all five values.
+	if r.reads != 3 {
+		t.Errorf("ReadDirectory issued %d reads, want 3", r.reads)
+	}
+	if s, _ := d.ASCII(tifftag.Artist); s != "an artist" {
+		t.Errorf("ASCII(Artist) = %q", s)
+	}
+	if r, _ := d.Rational(tifftag.YResolution); r.Num != 72 {
+		t.Errorf("Rational(YResolution) = %v", r)
+	}
+}
+
+func TestReadDirectoryUnreadableValue(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(2, 2),
+			tifftest.ASCII(tifftag.ImageDescription, "a description"),
+			tifftest.ASCII(tifftag.Artist, "an artist")),
+		Blocks: [][]byte{make([]byte, 4)},
+	})
+	// Out-of-line values follow the directory in tag order, so truncating
+	// the file cuts off the value of Artist.
+	data = data[:len(data)-4]
+
+	d, err := ifd.ReadAll(bytes.NewReader(data), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := d[0]
+	if s, ok := dir.ASCII(tifftag.ImageDescription); !ok || s != "a description" {
+		t.Errorf("ASCII(ImageDescription) = %q, %v", s, ok)
+	}
+	if _, ok := dir.ASCII(tifftag.Artist); ok {
+		t.Errorf("ASCII(Artist) succeeded for an unreadable value")
+	}
+	if e, ok := dir.Entry(tifftag.Artist); !ok || e.Err == nil {
+		t.Errorf("Entry(Artist) = %v, %v, want entry with Err", e, ok)
+	}
+	if !dir.Has(tifftag.Artist) {
+		t.Errorf("Has(Artist) = false")
+	}
+	if dir.Err() == nil {
+		t.Errorf("Err() = nil")
+	}
+	if err := dir.Validate(); !errors.Is(err, ifd.ErrInvalidDirectory) {
+		t.Errorf("Validate() = %v, want ErrInvalidDirectory", err)
+	}
+	if w, ok := dir.Uint(tifftag.ImageWidth); !ok || w != 2 {
+		t.Errorf("Uint(ImageWidth) = %d, %v", w, ok)
+	}
+}
+
+// sizeReader records the largest read from a bytes.Reader.
+type sizeReader struct {
+	*bytes.Reader
+	largest int
+}
+
+func (r *sizeReader) ReadAt(p []byte, off int64) (int, error) {
+	r.largest = max(r.largest, len(p))
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestReadDirectoryValueBounds(t *testing.T) {
+	const private = tifftag.Tag(65000)
+	tests := []struct {
+		name          string
+		count, offset uint32 // 0 keeps the value of the file
+	}{
+		{"beyond the file", 1 << 24, 0},
+		{"too large", 1 << 30, 0},
+		{"offset beyond the file", 0, 1 << 31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tifftest.Build(tifftest.IFD{
+				Entries: tifftest.With(tifftest.Gray(1, 1), tifftest.Long(private, 1, 2, 3)),
+				Blocks:  [][]byte{{0}},
+			})
+			// Patch the count and value offset of the private entry.
+			dir := int(binary.LittleEndian.Uint32(data[4:8]))
+			n := int(binary.LittleEndian.Uint16(data[dir:]))
+			for i := 0; i < n; i++ {
+				e := data[dir+2+12*i:]
+				if tifftag.Tag(binary.LittleEndian.Uint16(e)) != private {
+					continue
+				}
+				if tt.count != 0 {
+					binary.LittleEndian.PutUint32(e[4:], tt.count)
+				}
+				if tt.offset != 0 {
+					binary.LittleEndian.PutUint32(e[8:], tt.offset)
+				}
+			}
+
+			r := &sizeReader{Reader: bytes.NewReader(data)}
+			d, err := ifd.ReadAll(r, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e, ok := d[0].Entry(private); !ok || e.Err == nil {
+				t.Errorf("Entry() = %v, %v, want entry with Err", e, ok)
+			}
+			if w, ok := d[0].Uint(tifftag.ImageWidth); !ok || w != 1 {
+				t.Errorf("Uint(ImageWidth) = %d, %v", w, ok)
+			}
+			if r.largest > len(data) {
+				t.Errorf("read %d bytes from a %d byte file", r.largest, len(data))
+			}
+		})
+	}
+}
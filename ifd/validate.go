@@ -0,0 +1,49 @@
+package ifd
+
+import (
+	"fmt"
+
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// Validate checks the directory against the TIFF specification: entries must
+// be sorted by ascending tag without duplicates, and every registered tag
+// (see tifftag.Lookup) must use an allowed field type and the expected
+// number of values. Unregistered tags are not checked. Values that could
+// not be read are reported too.
+//
+// Readers are lenient by default; Validate backs their strict mode.
+func (d *Directory) Validate() error {
+	samples := uint64(1)
+	if v, ok := d.Uint(tifftag.SamplesPerPixel); ok {
+		samples = v
+	}
+
+	for i, e := range d.Entries {
+		if i > 0 && e.Tag <= d.Entries[i-1].Tag {
+			return fmt.Errorf("%w: %s out of order after %s", ErrInvalidDirectory, e.Tag, d.Entries[i-1].Tag)
+		}
+		if e.Err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidDirectory, e.Err)
+		}
+		info, ok := tifftag.Lookup(e.Tag)
+		if !ok {
+			continue
+		}
+		if !info.Accepts(e.Type) {
+			return fmt.Errorf("%w: %s has field type %s, want one of %v", ErrInvalidDirectory, e.Tag, e.Type, info.Types)
+		}
+		switch info.Count {
+		case tifftag.AnyCount:
+		case tifftag.PerSampleCount:
+			if e.Count != samples {
+				return fmt.Errorf("%w: %s has %d values, want %d (one per sample)", ErrInvalidDirectory, e.Tag, e.Count, samples)
+			}
+		default:
+			if e.Count != uint64(info.Count) {
+				return fmt.Errorf("%w: %s has %d values, want %d", ErrInvalidDirectory, e.Tag, e.Count, info.Count)
+			}
+		}
+	}
+	return nil
+}
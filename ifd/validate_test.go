@@ -0,0 +1,64 @@
+package ifd_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestValidate(t *testing.T) {
+	rgb := tifftest.Image(2, 2, 2, 3)
+	tests := []struct {
+		name    string
+		entries []tifftest.Entry
+		wantErr bool
+	}{
+		{"valid", rgb, false},
+		{"unregistered tag", tifftest.With(rgb, tifftest.Short(60000, 1, 2, 3)), false},
+		{"field type", tifftest.With(rgb, tifftest.ASCII(tifftag.Orientation, "1")), true},
+		{"count", tifftest.With(rgb, tifftest.Short(tifftag.Orientation, 1, 1)), true},
+		{"count per sample", tifftest.With(rgb, tifftest.Short(tifftag.BitsPerSample, 8)), true},
+		{"rational", tifftest.With(rgb, tifftest.Entry{Tag: tifftag.XResolution, Type: fieldtype.Short, Values: []uint64{72}}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tifftest.Build(tifftest.IFD{Entries: tt.entries, Blocks: [][]byte{make([]byte, 12)}})
+			dirs, err := ifd.ReadAll(bytes.NewReader(data), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = dirs[0].Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ifd.ErrInvalidDirectory) {
+				t.Errorf("Validate() = %v, want ErrInvalidDirectory", err)
+			}
+		})
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(2, 2), Blocks: [][]byte{make([]byte, 4)}})
+	off := int64(binary.LittleEndian.Uint32(data[4:]))
+
+	// Swap the first two entries.
+	first, second := data[off+2:off+14], data[off+14:off+26]
+	swapped := append([]byte(nil), first...)
+	copy(first, second)
+	copy(second, swapped)
+
+	dirs, err := ifd.ReadAll(bytes.NewReader(data), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dirs[0].Validate(); !errors.Is(err, ifd.ErrInvalidDirectory) {
+		t.Errorf("Validate() = %v, want ErrInvalidDirectory", err)
+	}
+}
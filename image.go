@@ -0,0 +1,125 @@
+package tiff
+
+import (
+	"context"
+	"image"
+	"image/color"
+
+	"github.com/echoflaresat/tiff/blockcache"
+	"github.com/echoflaresat/tiff/exif"
+	"github.com/echoflaresat/tiff/gdal"
+	"github.com/echoflaresat/tiff/geotiff"
+	"github.com/echoflaresat/tiff/icc"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/iptc"
+	"github.com/echoflaresat/tiff/xmp"
+)
+
+// Image is implemented by the images returned by Decode when the random-access
+// path is used. Images decoded by the golang.org/x/image/tiff fallback do not
+// implement it.
+//
+// Besides the image.Image methods, it gives access to the individual bands
+// (samples per pixel) of the image, which is useful for multispectral and
+// hyperspectral rasters with more bands than can be displayed as RGB.
+type Image interface {
+	image.Image
+
+	// AtContext returns the color of the pixel at (x, y) like At, reading
+	// the underlying strip or tile on behalf of ctx. I/O errors and
+	// cancellation are returned instead of causing a panic.
+	AtContext(ctx context.Context, x, y int) (color.Color, error)
+
+	// BandCount returns the number of bands per pixel, including alpha
+	// and unspecified extra samples.
+	BandCount() int
+
+	// Band returns a lazy grayscale view of band i.
+	Band(i int) image.Image
+
+	// ReadBands copies the samples of the given bands (all bands if empty)
+	// for every pixel in r into dst, pixel-interleaved in row-major order.
+	ReadBands(r image.Rectangle, bands []int, dst []byte) error
+
+	// ReadRegion is ReadBands with the strips or tiles intersecting r decoded
+	// in parallel by up to concurrency goroutines (GOMAXPROCS if <= 0).
+	// Uncached blocks close together in the file are fetched with merged
+	// reads (see Options.CoalesceGap). It stops early and returns ctx.Err()
+	// if ctx is cancelled.
+	ReadRegion(ctx context.Context, r image.Rectangle, bands []int, dst []byte, concurrency int) error
+
+	// Prefetch starts loading the strips or tiles covering r into the block
+	// cache in the background and returns immediately, e.g. for the area a
+	// viewer is about to pan to. Errors surface when the pixels are read.
+	Prefetch(r image.Rectangle)
+
+	// DisplayBands returns a view of the image that renders bands r, g and b
+	// as red, green and blue.
+	DisplayBands(r, g, b int) (image.Image, error)
+
+	// Close cancels background prefetches, waits for them and for pixel
+	// reads in progress, removes the blocks of the image from the block
+	// cache and closes the temporary file its input was spooled to, if any.
+	// Pixel and metadata reads through the image and its views then return
+	// ErrClosed, and At panics. The reader given to Decode is not closed.
+	Close() error
+
+	// Mask returns the transparency mask subfile applied to the image as a
+	// lazy RGBA image, black where the image is transparent and white where
+	// it is opaque, or nil if there is none or it cannot be decoded.
+	Mask() image.Image
+
+	// Directory returns the parsed IFD of the image. It holds every tag of
+	// the directory, including unknown ones as raw bytes, with typed lookups
+	// such as ASCII(tifftag.Software) or Rational(tifftag.XResolution).
+	Directory() *ifd.Directory
+
+	// Exif parses the EXIF sub-directory (exposure, lens, timestamps).
+	// It returns exif.ErrNotFound if the image has none.
+	Exif() (*exif.Exif, error)
+
+	// GPS parses the GPS sub-directory (latitude, longitude, altitude, time).
+	// It returns exif.ErrNotFound if the image has none.
+	GPS() (*exif.GPS, error)
+
+	// ICCProfile returns the embedded ICC profile (tag 34675) with its decoded
+	// header and description. It returns icc.ErrNotFound if there is none.
+	ICCProfile() (*icc.Profile, error)
+
+	// XMP returns the embedded XMP packet (tag 700) as XML with its simple
+	// properties. It returns xmp.ErrNotFound if there is none.
+	XMP() (*xmp.Packet, error)
+
+	// IPTC returns the embedded IPTC/NAA block (tag 33723) decoded into
+	// datasets. It returns iptc.ErrNotFound if there is none.
+	IPTC() (*iptc.Block, error)
+
+	// Georeference parses the GeoTIFF tags of the image: the affine
+	// geotransform, raster type, CRS codes and all GeoKeys. It returns
+	// geotiff.ErrNotGeoreferenced if the image carries none.
+	Georeference() (*geotiff.Georeference, error)
+
+	// GDALMetadata parses the GDAL_NODATA and GDAL_METADATA tags: the nodata
+	// value and per-band scale, offset, description and statistics.
+	GDALMetadata() (*gdal.Metadata, error)
+
+	// PixelToWorld converts pixel coordinates to coordinates of the image
+	// CRS. (0, 0) is the top-left corner of the top-left pixel.
+	PixelToWorld(col, row float64) (x, y float64, err error)
+
+	// WorldToPixel converts coordinates of the image CRS to (possibly
+	// fractional) pixel coordinates.
+	WorldToPixel(x, y float64) (col, row float64, err error)
+
+	// ReadWindow returns a lazy view of the pixels covering the bounding box,
+	// given in the image CRS and clipped to the image bounds.
+	ReadWindow(b geotiff.BBox) (image.Image, error)
+
+	// SubImage returns a lazy view of the part of the image visible through r,
+	// keeping the coordinates of the image.
+	SubImage(r image.Rectangle) image.Image
+
+	// CacheStats returns the hits, misses, evictions and cached bytes of the
+	// image in its block cache.
+	CacheStats() blockcache.Stats
+}
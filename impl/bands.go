@@ -0,0 +1,103 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file implements access to the individual bands of multi-band images.
+package impl
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// BandCount returns the number of bands (samples per pixel) stored in the image,
+// including alpha and unspecified extra samples.
+func (l *lazyImage) BandCount() int {
+	return l.format.samples
+}
+
+// Band returns a lazy grayscale view of band i.
+// The view shares the block cache of l; it panics if i is out of range.
+func (l *lazyImage) Band(i int) image.Image {
+	if i < 0 || i >= l.format.samples {
+		panic(fmt.Sprintf("band %d out of range [0, %d)", i, l.format.samples))
+	}
+	return &bandImage{src: l, band: i}
+}
+
+// ReadBands copies the samples of the given bands for every pixel in r into dst.
+//
+// Samples are written pixel-interleaved in row-major order, i.e. dst holds
+// len(bands) bytes per pixel in the order the bands are listed. A nil or empty
+// bands slice selects all bands. dst must hold at least
+// r.Dx()*r.Dy()*len(bands) bytes and r must lie within the image bounds.
+func (l *lazyImage) ReadBands(r image.Rectangle, bands []int, dst []byte) error {
+	bands, err := l.checkRegion(r, bands, dst)
+	if err != nil {
+		return err
+	}
+	end, err := l.state.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
+	i := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			px, err := l.pixel(context.Background(), x, y)
+			if err != nil {
+				return err
+			}
+			for _, b := range bands {
+				dst[i] = px[b]
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+// DisplayBands returns a view of the image that renders bands r, g and b as
+// red, green and blue. Alpha handling is unchanged.
+func (l *lazyImage) DisplayBands(r, g, b int) (image.Image, error) {
+	format, err := l.format.withBands(r, g, b)
+	if err != nil {
+		return nil, err
+	}
+	view := *l
+	view.format = format
+	return &view, nil
+}
+
+// bandImage is a lazy grayscale view of a single band of a lazyImage.
+type bandImage struct {
+	src  *lazyImage
+	band int
+}
+
+// ColorModel returns color.GrayModel.
+func (b *bandImage) ColorModel() color.Model {
+	return color.GrayModel
+}
+
+// Bounds returns the bounds of the source image.
+func (b *bandImage) Bounds() image.Rectangle {
+	return b.src.Bounds()
+}
+
+// At returns the sample of the band at (x, y) as a color.Gray.
+func (b *bandImage) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(b.Bounds())) {
+		return color.Gray{}
+	}
+	end, err := b.src.state.begin()
+	if err != nil {
+		panic(err.Error())
+	}
+	defer end()
+	px, err := b.src.pixel(context.Background(), x, y)
+	if err != nil {
+		panic(err.Error())
+	}
+	return color.Gray{Y: px[b.band]}
+}
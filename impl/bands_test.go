@@ -0,0 +1,123 @@
+package impl
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// multiband returns a w × h image of five bands, RGB followed by two
+// unspecified extra samples; sample b of pixel (x, y) is 40*b + 10*y + x.
+func multiband(t *testing.T, w, h int) *lazyImage {
+	t.Helper()
+	var pix []byte
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for b := 0; b < 5; b++ {
+				pix = append(pix, byte(40*b+10*y+x))
+			}
+		}
+	}
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Image(w, h, 2, 5),
+			tifftest.Short(tifftag.ExtraSamples, 0, 0),
+			tifftest.Short(tifftag.RowsPerStrip, uint64(h))),
+		Blocks: [][]byte{pix},
+	})
+	img, err := LoadStripedTiff(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img.(*stripedTiff).lazyImage
+}
+
+func TestBand(t *testing.T) {
+	l := multiband(t, 3, 2)
+	if got := l.BandCount(); got != 5 {
+		t.Fatalf("BandCount() = %d, want 5", got)
+	}
+	for b := 0; b < 5; b++ {
+		band := l.Band(b)
+		if got := band.Bounds(); got != l.Bounds() {
+			t.Errorf("Band(%d).Bounds() = %v, want %v", b, got, l.Bounds())
+		}
+		if got, want := band.At(2, 1), (color.Gray{Y: byte(40*b + 12)}); got != want {
+			t.Errorf("Band(%d).At(2, 1) = %v, want %v", b, got, want)
+		}
+	}
+	for _, b := range []int{-1, 5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Band(%d) did not panic", b)
+				}
+			}()
+			l.Band(b)
+		}()
+	}
+}
+
+func TestReadBands(t *testing.T) {
+	l := multiband(t, 3, 2)
+	r := image.Rect(1, 0, 3, 2)
+	tests := []struct {
+		name  string
+		bands []int
+		want  []byte
+	}{
+		{"all", nil, []byte{
+			1, 41, 81, 121, 161, 2, 42, 82, 122, 162,
+			11, 51, 91, 131, 171, 12, 52, 92, 132, 172,
+		}},
+		{"reordered", []int{4, 0}, []byte{161, 1, 162, 2, 171, 11, 172, 12}},
+		{"repeated", []int{2, 2}, []byte{81, 81, 82, 82, 91, 91, 92, 92}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]byte, len(tt.want))
+			if err := l.ReadBands(r, tt.bands, dst); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(dst, tt.want) {
+				t.Errorf("ReadBands() = %v, want %v", dst, tt.want)
+			}
+		})
+	}
+
+	errs := []struct {
+		name  string
+		r     image.Rectangle
+		bands []int
+		dst   int
+	}{
+		{"band too large", r, []int{5}, 4},
+		{"negative band", r, []int{-1}, 4},
+		{"dst too small", r, []int{0, 1}, 7},
+		{"outside bounds", image.Rect(2, 0, 4, 2), []int{0}, 4},
+	}
+	for _, tt := range errs {
+		if err := l.ReadBands(tt.r, tt.bands, make([]byte, tt.dst)); err == nil {
+			t.Errorf("%s: ReadBands() succeeded", tt.name)
+		}
+	}
+}
+
+func TestDisplayBands(t *testing.T) {
+	l := multiband(t, 3, 2)
+	img, err := l.DisplayBands(4, 3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := img.At(1, 1), (color.RGBA{171, 131, 11, 255}); got != want {
+		t.Errorf("At(1, 1) = %v, want %v", got, want)
+	}
+	for _, bands := range [][3]int{{5, 0, 0}, {0, -1, 0}, {0, 0, 7}} {
+		if _, err := l.DisplayBands(bands[0], bands[1], bands[2]); err == nil {
+			t.Errorf("DisplayBands%v succeeded", bands)
+		}
+	}
+}
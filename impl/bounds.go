@@ -0,0 +1,61 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file guards allocations against corrupt sizes in the file.
+package impl
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxBlockBytes bounds the size of a single strip or tile, stored or
+// decoded, and thus of every allocation made for one block. Stored blocks
+// are also checked against the size of the reader when it is known.
+const maxBlockBytes = 1 << 30
+
+// maxDimension bounds the width and height of an image and its tiles.
+const maxDimension = 1 << 30
+
+// readerSize returns the size of r, or -1 if it cannot be determined.
+func readerSize(r io.ReaderAt) int64 {
+	switch r := r.(type) {
+	case interface{ Size() int64 }:
+		return r.Size()
+	case *os.File:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
+// checkLayout rejects headers whose dimensions or block sizes would lead to
+// oversized allocations or overflowing block counts.
+func checkLayout(h TiffHeader, f pixelFormat) error {
+	if h.Width <= 0 || h.Height <= 0 || h.Width > maxDimension || h.Height > maxDimension {
+		return fmt.Errorf("invalid image size %dx%d", h.Width, h.Height)
+	}
+	if len(h.TileOffsets) > 0 {
+		if h.TileWidth <= 0 || h.TileHeight <= 0 || h.TileWidth > maxDimension || h.TileHeight > maxDimension {
+			return fmt.Errorf("invalid tile size %dx%d", h.TileWidth, h.TileHeight)
+		}
+		if int64(f.rowBytes(h.TileWidth))*int64(h.TileHeight) > maxBlockBytes {
+			return fmt.Errorf("tile size %dx%d too large", h.TileWidth, h.TileHeight)
+		}
+		return nil
+	}
+	if int64(f.rowBytes(h.Width)) > maxBlockBytes {
+		return fmt.Errorf("image width %d too large", h.Width)
+	}
+	return nil
+}
+
+// checkBlock returns an error unless the n bytes at off lie within the
+// reader of l and n does not exceed maxBlockBytes. It is called before
+// buffers for stored blocks are allocated.
+func (l *lazyImage) checkBlock(off, n int64) error {
+	if off < 0 || n < 0 || n > maxBlockBytes || (l.size >= 0 && off > l.size-n) {
+		return fmt.Errorf("block of %d bytes at offset %d outside the file", n, off)
+	}
+	return nil
+}
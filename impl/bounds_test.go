@@ -0,0 +1,83 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"testing"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func long8(tag tifftag.Tag, v ...uint64) tifftest.Entry {
+	return tifftest.Entry{Tag: tag, Type: fieldtype.Long8, Values: v}
+}
+
+func TestCorruptBlockSizes(t *testing.T) {
+	tiled := func(offset, count uint64) []tifftest.Entry {
+		return tifftest.With(tifftest.Image(16, 16, 1, 1),
+			tifftest.Short(tifftag.TileWidth, 16),
+			tifftest.Short(tifftag.TileLength, 16),
+			long8(tifftag.TileOffsets, offset),
+			long8(tifftag.TileByteCounts, count))
+	}
+	striped := func(offset, count uint64) []tifftest.Entry {
+		return tifftest.With(tifftest.Gray(16, 16),
+			long8(tifftag.StripOffsets, offset),
+			long8(tifftag.StripByteCounts, count))
+	}
+	tests := []struct {
+		name    string
+		entries []tifftest.Entry
+	}{
+		{"tile count beyond file", tiled(16, 1<<40)},
+		{"negative tile count", tiled(16, 1<<63)},
+		{"negative tile offset", tiled(1<<63, 256)},
+		{"tile offset beyond file", tiled(1<<40, 256)},
+		{"strip offset beyond file", striped(1<<40, 256)},
+		{"negative strip offset", striped(1<<63, 256)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tifftest.File{BigTIFF: true, IFDs: []tifftest.IFD{{Entries: tt.entries}}}.Bytes()
+			img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+			if err != nil {
+				return // rejected up front
+			}
+			defer img.(*lazyImage).Close()
+			if _, err := img.(*lazyImage).AtContext(context.Background(), 1, 1); err == nil {
+				t.Error("AtContext() succeeded on a corrupt block")
+			}
+			dst := make([]byte, 16*16)
+			if err := img.(*lazyImage).ReadRegion(context.Background(), image.Rect(0, 0, 16, 16), nil, dst, 1); err == nil {
+				t.Error("ReadRegion() succeeded on a corrupt block")
+			}
+		})
+	}
+}
+
+func TestCorruptLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tifftest.Entry
+		tiled   bool
+	}{
+		{"huge width", tifftest.With(tifftest.Gray(1, 1), long8(tifftag.ImageWidth, 1<<40)), false},
+		{"negative width", tifftest.With(tifftest.Gray(1, 1), long8(tifftag.ImageWidth, 1<<63)), false},
+		{"huge tile", tifftest.With(tifftest.Image(1, 1, 1, 1),
+			long8(tifftag.TileWidth, 1<<20),
+			long8(tifftag.TileLength, 1<<20)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ifd := tifftest.IFD{Entries: tt.entries, Blocks: [][]byte{{0}}, Tiled: tt.tiled}
+			data := tifftest.File{BigTIFF: true, IFDs: []tifftest.IFD{ifd}}.Bytes()
+			if img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{}); err == nil {
+				img.(*lazyImage).Close()
+				t.Error("Load() succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file releases the resources held by lazy images.
+package impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed is returned when pixel data of a closed image is read.
+var ErrClosed = errors.New("tiff: image closed")
+
+// imageState is the mutable state shared by an image and its views.
+type imageState struct {
+	closed atomic.Bool
+
+	// ctx is cancelled by Close to stop background loads.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex     // serializes starting loads and reads with Close
+	loads sync.WaitGroup // background loads in progress
+	reads sync.WaitGroup // pixel reads in progress, see begin
+
+	// readAhead is the number of blocks loaded ahead of the block being
+	// read, or 0 if sequential access is disabled. scheduled is the last
+	// block index handed to a read-ahead load and current the block index
+	// of the last pixel read plus one, so that its zero value matches none.
+	readAhead int
+	scheduled atomic.Int64
+	current   atomic.Int64
+}
+
+// newImageState returns the state of a new open image.
+func newImageState() *imageState {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &imageState{ctx: ctx, cancel: cancel}
+}
+
+// background runs fn in a new goroutine unless the image has been closed.
+// The context passed to fn is cancelled when Close starts; Close then waits
+// for fn to return.
+func (s *imageState) background(fn func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed.Load() {
+		return
+	}
+	s.loads.Add(1)
+	go func() {
+		defer s.loads.Done()
+		fn(s.ctx)
+	}()
+}
+
+// begin registers a pixel read and returns the function ending it, or
+// ErrClosed if the image has been closed. Close waits for registered reads,
+// so the reader, and pixel data sliced from a memory-mapped reader, stay
+// valid until end is called. Reads may be nested.
+func (s *imageState) begin() (end func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed.Load() {
+		return nil, ErrClosed
+	}
+	s.reads.Add(1)
+	return s.reads.Done, nil
+}
+
+// Close releases the image: background loads in progress are cancelled,
+// they and pixel reads in progress are waited for, its blocks and those of
+// its transparency mask are removed from the block cache, and reads issued
+// afterwards through the image or any view derived from it return ErrClosed.
+// The reader the image was decoded from is closed only if the image owns it
+// (see LoadOptions.Owned).
+//
+// Closing an image a second time returns ErrClosed.
+func (l *lazyImage) Close() error {
+	l.state.mu.Lock()
+	closed := l.state.closed.Swap(true)
+	l.state.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	l.state.cancel()
+	l.state.loads.Wait()
+	l.state.reads.Wait()
+
+	l.cache.Purge()
+	if l.mask != nil {
+		_ = l.mask.Close()
+	}
+	if l.owned != nil {
+		return l.owned.Close()
+	}
+	return nil
+}
+
+// checkOpen returns ErrClosed if the image has been closed.
+func (l *lazyImage) checkOpen() error {
+	if l.state.closed.Load() {
+		return ErrClosed
+	}
+	return nil
+}
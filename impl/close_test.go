@@ -0,0 +1,147 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closer records whether it was closed.
+type closer struct{ closed bool }
+
+func (c *closer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	data, _ := tiledGray(32, 32)
+	owned := &closer{}
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20, Owned: owned})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	view := l.SubImage(image.Rect(8, 8, 24, 24)).(*lazyImage)
+	l.At(0, 0)
+	if l.CacheStats().Blocks == 0 {
+		t.Fatalf("no blocks cached")
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !owned.closed {
+		t.Errorf("owned reader not closed")
+	}
+	if s := l.CacheStats(); s.Blocks != 0 || s.Bytes != 0 {
+		t.Errorf("CacheStats() after Close = %+v, want no blocks", s)
+	}
+	if _, err := view.AtContext(context.Background(), 10, 10); !errors.Is(err, ErrClosed) {
+		t.Errorf("AtContext() of a view after Close = %v, want ErrClosed", err)
+	}
+	if err := l.ReadRegion(context.Background(), l.Bounds(), nil, make([]byte, 32*32), 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("ReadRegion() after Close = %v, want ErrClosed", err)
+	}
+	if err := l.ReadBands(l.Bounds(), nil, make([]byte, 32*32)); !errors.Is(err, ErrClosed) {
+		t.Errorf("ReadBands() after Close = %v, want ErrClosed", err)
+	}
+	if err := view.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("second Close() = %v, want ErrClosed", err)
+	}
+}
+
+// blockingReader blocks reads until release is closed.
+type blockingReader struct {
+	*bytes.Reader
+	started chan struct{}
+	once    sync.Once
+	release chan struct{}
+}
+
+func (r *blockingReader) ReadAt(p []byte, off int64) (int, error) {
+	r.once.Do(func() { close(r.started) })
+	<-r.release
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestCloseWaitsForReads(t *testing.T) {
+	data, _ := tiledGray(32, 32)
+	owned := &closer{}
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20, Owned: owned})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	r := &blockingReader{Reader: bytes.NewReader(data), started: make(chan struct{}), release: make(chan struct{})}
+	l.reader = r
+
+	read := make(chan error)
+	go func() {
+		_, err := l.AtContext(context.Background(), 0, 0)
+		read <- err
+	}()
+	<-r.started
+
+	closed := make(chan error)
+	go func() { closed <- l.Close() }()
+	select {
+	case <-closed:
+		t.Fatalf("Close returned while a read was in progress")
+	case err := <-read:
+		t.Fatalf("read returned early: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(r.release)
+	if err := <-read; err != nil {
+		t.Errorf("read in progress during Close = %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+	if !owned.closed {
+		t.Errorf("owned reader not closed")
+	}
+}
+
+// stallingReader blocks context reads until their context is done.
+type stallingReader struct {
+	*bytes.Reader
+	started chan struct{}
+	once    sync.Once
+}
+
+func (r *stallingReader) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	r.once.Do(func() { close(r.started) })
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+func TestCloseCancelsPrefetch(t *testing.T) {
+	data, _ := tiledGray(32, 32)
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	r := &stallingReader{Reader: bytes.NewReader(data), started: make(chan struct{})}
+	l.reader = r
+
+	l.Prefetch(l.Bounds())
+	<-r.started
+	closed := make(chan error)
+	go func() { closed <- l.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close() = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not cancel the prefetch in progress")
+	}
+}
@@ -0,0 +1,102 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file reports image configuration from the directories alone.
+package impl
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/echoflaresat/tiff/extrasample"
+	"github.com/echoflaresat/tiff/photometric"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ReadHeader parses the TIFF header and the first directory without reading
+// the rest of the IFD chain or pixel data.
+func ReadHeader(reader io.ReaderAt) (TiffHeader, error) {
+	return parseTiffHeader(reader)
+}
+
+// ReadHeaders parses the TIFF header and the directories of the IFD chain
+// without reading pixel data. Like Load, it only requires the first
+// directory to be readable: the chain ends at the first directory that
+// cannot be read.
+func ReadHeaders(reader io.ReaderAt) ([]TiffHeader, error) {
+	chain, err := newHeaderChain(reader)
+	if err != nil {
+		return nil, err
+	}
+	for chain.read() {
+	}
+	return chain.headers, nil
+}
+
+// Lazy reports whether the directory described by header can be decoded
+// with random access by the striped or tiled loader.
+func Lazy(header TiffHeader) bool {
+	_, err := loadDirectory(nil, header, nil)
+	return err == nil
+}
+
+// ColorModel returns the color model of the image described by header, as
+// produced by the lazy loaders for supported layouts and by the standard
+// decoder otherwise.
+func ColorModel(header TiffHeader) (color.Model, error) {
+	if l, err := loadDirectory(nil, header, nil); err == nil {
+		return l.format.colorModel(), nil
+	}
+
+	bits := 8
+	if len(header.BitsPerSample) > 0 {
+		bits = header.BitsPerSample[0]
+	}
+	unassociated := len(header.ExtraSamples) > 0 && header.ExtraSamples[0] == extrasample.UnassociatedAlpha
+
+	switch header.Photometric {
+	case photometric.WhiteIsZero, photometric.BlackIsZero, photometric.TransMask:
+		if bits == 16 {
+			return color.Gray16Model, nil
+		}
+		return color.GrayModel, nil
+	case photometric.RGB:
+		switch {
+		case bits == 16 && unassociated:
+			return color.NRGBA64Model, nil
+		case bits == 16:
+			return color.RGBA64Model, nil
+		case unassociated:
+			return color.NRGBAModel, nil
+		default:
+			return color.RGBAModel, nil
+		}
+	case photometric.Paletted:
+		return palette(header)
+	case photometric.CMYK:
+		return color.CMYKModel, nil
+	case photometric.YCbCr:
+		return color.YCbCrModel, nil
+	default:
+		return nil, fmt.Errorf("unsupported photometric: %d", header.Photometric)
+	}
+}
+
+// palette builds the color palette of a paletted image from its ColorMap,
+// which holds all red values, then all green values, then all blue values.
+func palette(header TiffHeader) (color.Palette, error) {
+	cmap, ok := header.Directory.Uints(tifftag.ColorMap)
+	if !ok || len(cmap) == 0 || len(cmap)%3 != 0 {
+		return nil, fmt.Errorf("invalid color map")
+	}
+	n := len(cmap) / 3
+	p := make(color.Palette, n)
+	for i := range p {
+		p[i] = color.RGBA64{
+			R: uint16(cmap[i]),
+			G: uint16(cmap[n+i]),
+			B: uint16(cmap[2*n+i]),
+			A: 0xffff,
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,48 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file propagates context cancellation into io.ReaderAt backends.
+package impl
+
+import (
+	"context"
+	"io"
+)
+
+// readerAtContext is implemented by readers that can abandon a read when its
+// context is cancelled, such as network backends.
+type readerAtContext interface {
+	ReadAtContext(ctx context.Context, p []byte, off int64) (int, error)
+}
+
+// readAt reads len(p) bytes at off from r on behalf of ctx. Readers
+// implementing ReadAtContext receive ctx; for others, ctx is checked before
+// the read is issued.
+func readAt(ctx context.Context, r io.ReaderAt, p []byte, off int64) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if rc, ok := r.(readerAtContext); ok {
+		return rc.ReadAtContext(ctx, p, off)
+	}
+	return r.ReadAt(p, off)
+}
+
+// WithContext returns an io.ReaderAt that performs every read of r on behalf
+// of ctx, for code that only accepts an io.ReaderAt, such as the directory
+// parser and the fallback decoder.
+func WithContext(ctx context.Context, r io.ReaderAt) io.ReaderAt {
+	if ctx.Done() == nil {
+		return r
+	}
+	return &contextReader{ctx: ctx, r: r}
+}
+
+// contextReader binds a context to an io.ReaderAt.
+type contextReader struct {
+	ctx context.Context
+	r   io.ReaderAt
+}
+
+// ReadAt implements io.ReaderAt.
+func (c *contextReader) ReadAt(p []byte, off int64) (int, error) {
+	return readAt(c.ctx, c.r, p, off)
+}
@@ -0,0 +1,131 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+)
+
+type ctxKey struct{}
+
+// contextReaderAt records the contexts of its reads.
+type contextReaderAt struct {
+	*bytes.Reader
+	plain int // reads without a context
+	ctxs  []context.Context
+}
+
+func (r *contextReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.plain++
+	return r.Reader.ReadAt(p, off)
+}
+
+func (r *contextReaderAt) ReadAtContext(ctx context.Context, p []byte, off int64) (int, error) {
+	r.ctxs = append(r.ctxs, ctx)
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestContextReads(t *testing.T) {
+	data := tifftest.Build(grayIFD(4, 4, 0, 0x40))
+	r := &contextReaderAt{Reader: bytes.NewReader(data)}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "load")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	img, err := Load(ctx, r, LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.plain != 0 || len(r.ctxs) == 0 || r.ctxs[0].Value(ctxKey{}) != "load" {
+		t.Fatalf("Load read %d times without context, contexts %v", r.plain, r.ctxs)
+	}
+
+	r.ctxs = nil
+	pixelCtx := context.WithValue(context.Background(), ctxKey{}, "pixel")
+	if _, err := img.(*lazyImage).AtContext(pixelCtx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.ctxs) != 1 || r.ctxs[0].Value(ctxKey{}) != "pixel" {
+		t.Errorf("AtContext read with contexts %v, want the one given", r.ctxs)
+	}
+}
+
+func TestContextCancelled(t *testing.T) {
+	data := tifftest.Build(grayIFD(4, 4, 0, 0x40))
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := img.(*lazyImage).AtContext(ctx, 0, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("AtContext(cancelled) = %v, want context.Canceled", err)
+	}
+	if _, err := Load(ctx, bytes.NewReader(data), LoadOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load(cancelled) = %v, want context.Canceled", err)
+	}
+	// The failed read is not cached.
+	if _, err := img.(*lazyImage).AtContext(context.Background(), 0, 0); err != nil {
+		t.Errorf("AtContext() after cancellation = %v", err)
+	}
+}
+
+func TestAtContextReadError(t *testing.T) {
+	data := tifftest.Build(grayIFD(4, 4, 0, 0x40))
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	l.reader = bytes.NewReader(nil)
+
+	if _, err := l.AtContext(context.Background(), 0, 0); err == nil {
+		t.Errorf("AtContext() succeeded on a failing reader")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("At() did not panic on a failing reader")
+		}
+	}()
+	l.At(0, 0)
+}
+
+// shortReaderAt returns no bytes and no error.
+type shortReaderAt struct{}
+
+func (shortReaderAt) ReadAt(p []byte, off int64) (int, error) { return 0, nil }
+
+func TestAtContextShortRead(t *testing.T) {
+	data := tifftest.Build(grayIFD(4, 4, 0, 0x40))
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	l.reader = shortReaderAt{}
+
+	if _, err := l.AtContext(context.Background(), 0, 0); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("AtContext() = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	r := bytes.NewReader([]byte{1, 2, 3})
+	if WithContext(context.Background(), r) != r {
+		t.Errorf("WithContext(Background) wrapped the reader")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cr := WithContext(ctx, r)
+	p := make([]byte, 3)
+	if _, err := cr.ReadAt(p, 0); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := cr.ReadAt(p, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadAt after cancel = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file plans and performs coalesced reads of strips and tiles.
+package impl
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+)
+
+// Default thresholds for merging the byte ranges of nearby blocks.
+const (
+	DefaultCoalesceGap      = 32 << 10
+	DefaultCoalesceMaxBytes = 4 << 20
+)
+
+// blockRange locates the stored bytes of a cached block in the file.
+type blockRange struct {
+	key    uint64 // cache key of the decoded block
+	offset int64
+	length int64
+
+	// decode turns the stored bytes into the cached block. raw is part of a
+	// larger read buffer and must not be retained.
+	decode func(raw []byte) ([]byte, error)
+}
+
+// end returns the offset just past the block.
+func (b blockRange) end() int64 {
+	return b.offset + b.length
+}
+
+// fetch is a single read covering the byte ranges of one or more blocks,
+// including the gaps between them.
+type fetch struct {
+	offset, length int64
+	blocks         []blockRange
+}
+
+// planFetches groups blocks into reads in file order. A block joins the
+// previous read if at most gap bytes separate them and the read stays within
+// maxBytes; a block larger than maxBytes is read on its own. A negative gap
+// reads every block separately. Empty blocks are skipped.
+func planFetches(blocks []blockRange, gap, maxBytes int64) []fetch {
+	sorted := make([]blockRange, 0, len(blocks))
+	for _, b := range blocks {
+		if b.length > 0 && b.offset >= 0 {
+			sorted = append(sorted, b)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].offset < sorted[j].offset })
+
+	var fetches []fetch
+	for _, b := range sorted {
+		if n := len(fetches); n > 0 && gap >= 0 {
+			last := &fetches[n-1]
+			end := last.offset + last.length
+			if b.offset-end <= gap && max(end, b.end())-last.offset <= maxBytes {
+				last.length = max(end, b.end()) - last.offset
+				last.blocks = append(last.blocks, b)
+				continue
+			}
+		}
+		fetches = append(fetches, fetch{offset: b.offset, length: b.length, blocks: []blockRange{b}})
+	}
+	return fetches
+}
+
+// prefetch loads the uncached blocks covering units, given in display
+// coordinates, into the cache with coalesced reads for ReadRegion. It does
+// nothing unless coalescing is enabled.
+func (l *lazyImage) prefetch(ctx context.Context, units []image.Rectangle, concurrency int) error {
+	if l.blocks == nil || l.coalesceMaxBytes <= 0 {
+		return nil
+	}
+	var blocks []blockRange
+	for _, u := range units {
+		blocks = append(blocks, l.blocks(l.storedRect(u))...)
+	}
+	return l.fetchBlocks(ctx, blocks, concurrency)
+}
+
+// fetchBlocks loads the uncached blocks into the cache on up to concurrency
+// goroutines, merging the byte ranges of nearby blocks into single reads if
+// coalescing is enabled. Uncompressed blocks of memory-backed readers are
+// read in place and never loaded.
+//
+// Loading is best effort: errors are not cached, so blocks whose read or
+// decoding fails are loaded again when their pixels are read. Only the
+// cancellation of ctx is returned.
+func (l *lazyImage) fetchBlocks(ctx context.Context, blocks []blockRange, concurrency int) error {
+	if l.inPlace() {
+		return nil
+	}
+	var missing []blockRange
+	for _, b := range blocks {
+		// Blocks outside the file fail when their pixels are read.
+		if !l.cache.Contains(b.key) && l.checkBlock(b.offset, b.length) == nil {
+			missing = append(missing, b)
+		}
+	}
+	gap := l.coalesceGap
+	if l.coalesceMaxBytes <= 0 {
+		gap = -1
+	}
+	fetches := planFetches(missing, gap, l.coalesceMaxBytes)
+	return parallel(ctx, len(fetches), concurrency, func(ctx context.Context, i int) error {
+		l.load(ctx, fetches[i])
+		return ctx.Err()
+	})
+}
+
+// load reads f and caches its blocks, unless the image has been closed.
+// The read is issued by the load of the first uncached block, so concurrent
+// requests for that block wait for it instead of reading it again.
+func (l *lazyImage) load(ctx context.Context, f fetch) {
+	var buf []byte
+	var err error
+	read := func(ctx context.Context) ([]byte, error) {
+		if buf == nil && err == nil {
+			buf, err = l.readFetch(ctx, f)
+		}
+		return buf, err
+	}
+	for _, b := range f.blocks {
+		_, _ = l.cache.LoadContext(ctx, b.key, func(ctx context.Context) ([]byte, error) {
+			buf, err := read(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return b.decode(buf[b.offset-f.offset : b.end()-f.offset])
+		})
+	}
+}
+
+// readFetch reads the bytes covered by f.
+func (l *lazyImage) readFetch(ctx context.Context, f fetch) ([]byte, error) {
+	if err := l.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := l.checkBlock(f.offset, f.length); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, f.length)
+	n, err := readAt(ctx, l.reader, buf, f.offset)
+	if int64(n) != f.length {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("failed to read %d bytes at offset %d: %w", f.length, f.offset, err)
+	}
+	return buf, nil
+}
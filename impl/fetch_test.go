@@ -0,0 +1,50 @@
+package impl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanFetches(t *testing.T) {
+	block := func(key uint64, offset, length int64) blockRange {
+		return blockRange{key: key, offset: offset, length: length}
+	}
+	// Blocks out of file order, with an empty one.
+	blocks := []blockRange{
+		block(2, 300, 100),
+		block(0, 0, 100),
+		block(1, 110, 100),
+		block(3, 1000, 100),
+		block(4, 2000, 0),
+	}
+	tests := []struct {
+		name     string
+		gap, max int64
+		want     [][]uint64 // keys per fetch
+	}{
+		{"merge nearby", 100, 1 << 20, [][]uint64{{0, 1, 2}, {3}}},
+		{"no gap allowed", 0, 1 << 20, [][]uint64{{0}, {1}, {2}, {3}}},
+		{"max bytes", 1000, 250, [][]uint64{{0, 1}, {2}, {3}}},
+		{"whole file", 1000, 1 << 20, [][]uint64{{0, 1, 2, 3}}},
+		{"separate", -1, 1 << 20, [][]uint64{{0}, {1}, {2}, {3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]uint64
+			for _, f := range planFetches(blocks, tt.gap, tt.max) {
+				var keys []uint64
+				for _, b := range f.blocks {
+					keys = append(keys, b.key)
+				}
+				got = append(got, keys)
+				first, last := f.blocks[0], f.blocks[len(f.blocks)-1]
+				if f.offset != first.offset || f.offset+f.length != last.end() {
+					t.Errorf("fetch [%d, %d) does not span blocks %v", f.offset, f.offset+f.length, keys)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planFetches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file maps raw pixel samples to colors, including alpha handling.
+package impl
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/echoflaresat/tiff/extrasample"
+	"github.com/echoflaresat/tiff/photometric"
+	"github.com/echoflaresat/tiff/planarconfig"
+)
+
+// pixelFormat describes how the interleaved samples of one pixel are turned
+// into a color.Color.
+//
+// Color samples come first (one for grayscale, three for RGB), followed by the
+// extra samples listed in the ExtraSamples tag. The first associated or
+// unassociated alpha sample is used as alpha; unspecified extra samples are skipped.
+type pixelFormat struct {
+	samples   int              // samples stored per pixel
+	bits      int              // bits per sample: 8, or 1 for bilevel images and masks
+	rgb       [3]int           // indexes of the samples displayed as red, green and blue
+	alpha     int              // index of the alpha sample, or -1 if opaque
+	alphaType extrasample.Type // AssociatedAlpha or UnassociatedAlpha when alpha >= 0
+}
+
+// newPixelFormat validates the sample layout of the header and returns the
+// matching pixelFormat.
+//
+// Supported layouts are 8-bit RGB or BlackIsZero samples, optionally followed
+// by any number of extra samples, and single-sample 1-bit BlackIsZero or
+// TransMask images. Samples must be interleaved (PlanarConfig Contig); a
+// single sample stored as PlanarConfig Separate has the same layout.
+func newPixelFormat(h TiffHeader) (pixelFormat, error) {
+	if h.PlanarConfig != planarconfig.Contig && (h.PlanarConfig != planarconfig.Separate || h.SamplesPerPixel != 1) {
+		return pixelFormat{}, fmt.Errorf("unsupported planar configuration: %d", h.PlanarConfig)
+	}
+
+	var colors int
+	switch h.Photometric {
+	case photometric.BlackIsZero, photometric.TransMask:
+		colors = 1
+	case photometric.RGB:
+		colors = 3
+	default:
+		return pixelFormat{}, fmt.Errorf("unsupported photometric: %d", h.Photometric)
+	}
+
+	if h.SamplesPerPixel < colors {
+		return pixelFormat{}, fmt.Errorf("unsupported %s format: %d samples per pixel", h.Photometric, h.SamplesPerPixel)
+	}
+	if len(h.BitsPerSample) == 0 {
+		return pixelFormat{}, fmt.Errorf("missing bits per sample")
+	}
+	bits := h.BitsPerSample[0]
+	for _, b := range h.BitsPerSample {
+		if b != bits {
+			return pixelFormat{}, fmt.Errorf("unsupported %s format: mixed bits per sample %v", h.Photometric, h.BitsPerSample)
+		}
+	}
+	switch {
+	case bits == 8 && h.Photometric != photometric.TransMask:
+	case bits == 1 && h.SamplesPerPixel == 1:
+	default:
+		return pixelFormat{}, fmt.Errorf("unsupported %s format: %d bits per sample", h.Photometric, bits)
+	}
+
+	f := pixelFormat{
+		samples: h.SamplesPerPixel,
+		bits:    bits,
+		rgb:     [3]int{0, 1, 2},
+		alpha:   -1,
+	}
+	if colors == 1 {
+		f.rgb = [3]int{0, 0, 0}
+	}
+
+	// Extra samples not listed in the tag are treated as unspecified.
+	for i, extra := range h.ExtraSamples {
+		if colors+i >= h.SamplesPerPixel {
+			break
+		}
+		if extra == extrasample.AssociatedAlpha || extra == extrasample.UnassociatedAlpha {
+			f.alpha = colors + i
+			f.alphaType = extra
+			break
+		}
+	}
+
+	return f, nil
+}
+
+// bitLevels holds the 8-bit expansion of the two values of a 1-bit sample.
+var bitLevels = [2][]byte{{0x00}, {0xff}}
+
+// rowBytes returns the number of bytes used by a row of width pixels.
+// Rows of sub-byte samples are padded to a whole byte.
+func (f pixelFormat) rowBytes(width int) int {
+	return (width*f.samples*f.bits + 7) / 8
+}
+
+// sample returns the samples of pixel x within row, expanded to one byte per sample.
+// The returned slice must not be modified.
+func (f pixelFormat) sample(row []byte, x int) []byte {
+	if f.bits == 1 {
+		return bitLevels[row[x/8]>>(7-x%8)&1]
+	}
+	base := x * f.samples
+	return row[base : base+f.samples]
+}
+
+// colorModel returns the color model of the colors produced by color.
+func (f pixelFormat) colorModel() color.Model {
+	if f.alpha >= 0 && f.alphaType == extrasample.UnassociatedAlpha {
+		return color.NRGBAModel
+	}
+	return color.RGBAModel
+}
+
+// color converts the samples of a single pixel into a color.
+// px must hold at least f.samples bytes.
+//
+// Associated (premultiplied) alpha yields color.RGBA, unassociated alpha
+// yields color.NRGBA and images without alpha yield opaque color.RGBA.
+// Premultiplied color samples larger than alpha are clamped to it, so that
+// the result is a valid color.RGBA.
+func (f pixelFormat) color(px []byte) color.Color {
+	r, g, b := px[f.rgb[0]], px[f.rgb[1]], px[f.rgb[2]]
+
+	if f.alpha < 0 {
+		return color.RGBA{R: r, G: g, B: b, A: 255}
+	}
+	a := px[f.alpha]
+	if f.alphaType == extrasample.UnassociatedAlpha {
+		return color.NRGBA{R: r, G: g, B: b, A: a}
+	}
+	return color.RGBA{R: min(r, a), G: min(g, a), B: min(b, a), A: a}
+}
+
+// zero returns the transparent color used outside the image bounds.
+func (f pixelFormat) zero() color.Color {
+	if f.alpha >= 0 && f.alphaType == extrasample.UnassociatedAlpha {
+		return color.NRGBA{}
+	}
+	return color.RGBA{}
+}
+
+// withBands returns a copy of f that displays bands r, g and b as red, green and blue.
+func (f pixelFormat) withBands(r, g, b int) (pixelFormat, error) {
+	for _, band := range []int{r, g, b} {
+		if band < 0 || band >= f.samples {
+			return pixelFormat{}, fmt.Errorf("band %d out of range [0, %d)", band, f.samples)
+		}
+	}
+	f.rgb = [3]int{r, g, b}
+	return f, nil
+}
@@ -0,0 +1,40 @@
+package impl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestPlanarConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		planar  uint64
+		samples int
+		wantErr bool
+	}{
+		{"contig", 1, 3, false},
+		{"separate single sample", 2, 1, false},
+		{"separate", 2, 3, true},
+		{"unknown", 3, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			photometric := uint64(2)
+			if tt.samples == 1 {
+				photometric = 1
+			}
+			data := tifftest.Build(tifftest.IFD{
+				Entries: tifftest.With(tifftest.Image(2, 2, photometric, tt.samples),
+					tifftest.Short(tifftag.PlanarConfiguration, tt.planar)),
+				Blocks: [][]byte{make([]byte, 2*2*tt.samples)},
+			})
+			_, err := LoadStripedTiff(bytes.NewReader(data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadStripedTiff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file exposes the GeoTIFF georeferencing of lazy images.
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/echoflaresat/tiff/geotiff"
+	"github.com/echoflaresat/tiff/orientation"
+)
+
+// errReoriented is returned by the georeferencing helpers of images presented
+// in a display orientation other than the stored one.
+var errReoriented = errors.New("georeferencing is only available in stored orientation")
+
+// Georeference parses the GeoTIFF tags of the image.
+// It returns geotiff.ErrNotGeoreferenced if the image carries none.
+func (l *lazyImage) Georeference() (*geotiff.Georeference, error) {
+	return geotiff.Parse(l.header.Directory)
+}
+
+// PixelToWorld converts the pixel coordinates (col, row) to the coordinates
+// of the image CRS. (0, 0) is the top-left corner of the top-left pixel.
+func (l *lazyImage) PixelToWorld(col, row float64) (x, y float64, err error) {
+	g, err := l.geoTransform()
+	if err != nil {
+		return 0, 0, err
+	}
+	return g.PixelToModel(col, row)
+}
+
+// WorldToPixel converts coordinates of the image CRS to (possibly fractional)
+// pixel coordinates.
+func (l *lazyImage) WorldToPixel(x, y float64) (col, row float64, err error) {
+	g, err := l.geoTransform()
+	if err != nil {
+		return 0, 0, err
+	}
+	return g.ModelToPixel(x, y)
+}
+
+// ReadWindow returns a lazy view of the pixels covering the bounding box b,
+// given in the image CRS. The covering pixel rectangle is clipped to the
+// image bounds; pixels are read on demand through the block cache like any
+// other access.
+func (l *lazyImage) ReadWindow(b geotiff.BBox) (image.Image, error) {
+	g, err := l.geoTransform()
+	if err != nil {
+		return nil, err
+	}
+	r, err := g.PixelRect(b)
+	if err != nil {
+		return nil, err
+	}
+	r = r.Intersect(l.Bounds())
+	if r.Empty() {
+		return nil, fmt.Errorf("bounding box %+v does not intersect the image", b)
+	}
+	return l.SubImage(r), nil
+}
+
+// geoTransform returns the georeferencing of l, requiring an affine geotransform.
+func (l *lazyImage) geoTransform() (*geotiff.Georeference, error) {
+	if l.orientation != 0 && l.orientation != orientation.TopLeft {
+		return nil, errReoriented
+	}
+	g, err := l.Georeference()
+	if err != nil {
+		return nil, err
+	}
+	if !g.HasGeoTransform {
+		return nil, geotiff.ErrNoGeoTransform
+	}
+	return g, nil
+}
@@ -0,0 +1,61 @@
+package impl
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/echoflaresat/tiff/geotiff"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/orientation"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestGeoreferencedImage(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(8, 8),
+			tifftest.Double(tifftag.ModelPixelScale, 10, 10, 0),
+			tifftest.Double(tifftag.ModelTiepoint, 0, 0, 0, 1000, 2000, 0)),
+		Blocks: [][]byte{make([]byte, 64)},
+	})
+	img, err := LoadStripedTiff(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*stripedTiff).lazyImage
+
+	if x, y, err := l.PixelToWorld(2, 3); err != nil || x != 1020 || y != 1970 {
+		t.Errorf("PixelToWorld(2, 3) = %v, %v, %v", x, y, err)
+	}
+	if col, row, err := l.WorldToPixel(1025, 1995); err != nil || col != 2.5 || row != 0.5 {
+		t.Errorf("WorldToPixel() = %v, %v, %v", col, row, err)
+	}
+
+	win, err := l.ReadWindow(geotiff.BBox{MinX: 1015, MinY: 1900, MaxX: 1200, MaxY: 1985})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := win.Bounds(), image.Rect(1, 1, 8, 8); got != want {
+		t.Errorf("ReadWindow bounds = %v, want %v (clipped)", got, want)
+	}
+	if _, err := l.ReadWindow(geotiff.BBox{MinX: 0, MinY: 0, MaxX: 10, MaxY: 10}); err == nil {
+		t.Errorf("ReadWindow() outside the image succeeded")
+	}
+
+	rotated := l.orient(orientation.RightTop)
+	if _, _, err := rotated.PixelToWorld(0, 0); !errors.Is(err, errReoriented) {
+		t.Errorf("PixelToWorld() of a reoriented image = %v", err)
+	}
+}
+
+func TestNotGeoreferencedImage(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(1, 1), Blocks: [][]byte{{0}}})
+	img, err := LoadStripedTiff(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := img.(*stripedTiff).lazyImage.PixelToWorld(0, 0); !errors.Is(err, geotiff.ErrNotGeoreferenced) {
+		t.Errorf("PixelToWorld() = %v, want ErrNotGeoreferenced", err)
+	}
+}
@@ -4,21 +4,32 @@ package impl
 
 import (
 	"encoding/binary"
-	"errors"
+	"fmt"
 	"io"
 
 	"github.com/echoflaresat/tiff/compression"
+	"github.com/echoflaresat/tiff/extrasample"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/orientation"
 	"github.com/echoflaresat/tiff/photometric"
 	"github.com/echoflaresat/tiff/planarconfig"
+	"github.com/echoflaresat/tiff/subfile"
 	"github.com/echoflaresat/tiff/tifftag"
 )
 
 // TiffHeader represents a parsed TIFF IFD (Image File Directory) header.
 // It captures key fields used in both striped and tiled image access.
 type TiffHeader struct {
+	// Directory is the parsed IFD the header was extracted from,
+	// including all entries not represented by the fields below.
+	Directory *ifd.Directory
+
 	// ByteOrder indicates whether the TIFF uses little-endian or big-endian byte ordering.
 	ByteOrder binary.ByteOrder
 
+	// SubfileType flags reduced-resolution images, pages and transparency masks.
+	SubfileType subfile.Type
+
 	// Image dimensions.
 	Width, Height int
 
@@ -28,6 +39,8 @@ type TiffHeader struct {
 	Photometric     photometric.Interpretation
 	Compression     compression.Type
 	PlanarConfig    planarconfig.Type
+	Orientation     orientation.Type
+	ExtraSamples    []extrasample.Type // meaning of samples beyond the color channels
 
 	// Strip layout fields.
 	RowsPerStrip    int
@@ -41,141 +54,191 @@ type TiffHeader struct {
 	TileByteCounts []int
 }
 
+// maxSamplesPerPixel is the largest SamplesPerPixel value, the range of its
+// SHORT field type.
+const maxSamplesPerPixel = 1<<16 - 1
+
 // ErrInvalidTiffHeader is returned when the TIFF header is missing, malformed,
 // or not conforming to the expected structure (e.g., wrong magic number).
-var ErrInvalidTiffHeader = errors.New("invalid TIFF header")
+var ErrInvalidTiffHeader = ifd.ErrInvalidHeader
 
-// parseTiffHeader reads the TIFF header and directory entries (IFD) from the given reader.
+// parseTiffHeader reads the TIFF header and the first directory (IFD) from the given reader.
 // It supports both little- and big-endian TIFFs.
 // The returned TiffHeader includes parsed tag values for layout, compression, and format.
 func parseTiffHeader(reader io.ReaderAt) (TiffHeader, error) {
-	read := func(offset int64, size int) ([]byte, error) {
-		buf := make([]byte, size)
-		_, err := reader.ReadAt(buf, offset)
-		return buf, err
-	}
-
-	// Read the 8-byte TIFF header
-	header, err := read(0, 8)
+	headers, err := parseTiffHeaders(reader, 1)
 	if err != nil {
 		return TiffHeader{}, err
 	}
+	return headers[0], nil
+}
 
-	var bo binary.ByteOrder
-	switch string(header[0:2]) {
-	case "II":
-		bo = binary.LittleEndian
-	case "MM":
-		bo = binary.BigEndian
-	default:
-		return TiffHeader{}, ErrInvalidTiffHeader
+// parseTiffHeaders reads the TIFF header and follows the IFD chain, returning
+// one TiffHeader per directory in file order. At most limit directories are
+// parsed; a limit <= 0 parses the whole chain.
+func parseTiffHeaders(reader io.ReaderAt, limit int) ([]TiffHeader, error) {
+	dirs, err := ifd.ReadAll(reader, limit)
+	if err != nil {
+		return nil, err
 	}
-	if bo.Uint16(header[2:4]) != 42 {
-		return TiffHeader{}, ErrInvalidTiffHeader
+	headers := make([]TiffHeader, len(dirs))
+	for i, d := range dirs {
+		if headers[i], err = headerFromDirectory(d); err != nil {
+			return nil, err
+		}
 	}
-	ifdOffset := int64(bo.Uint32(header[4:8]))
+	return headers, nil
+}
+
+// headerChain parses the directories of a file on demand, so that loading
+// an image does not depend on the directories following it.
+type headerChain struct {
+	chain   *ifd.Chain
+	headers []TiffHeader
+	err     error // error that ended the chain, nil if it ended normally
+}
 
-	// Read number of IFD entries
-	entryCountRaw, err := read(ifdOffset, 2)
+// newHeaderChain reads the TIFF header and the first directory of reader.
+func newHeaderChain(reader io.ReaderAt) (*headerChain, error) {
+	chain, err := ifd.NewChain(reader)
 	if err != nil {
-		return TiffHeader{}, err
+		return nil, err
+	}
+	c := &headerChain{chain: chain}
+	if !c.read() {
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, ErrInvalidTiffHeader
+	}
+	return c, nil
+}
+
+// read parses the next directory of the chain and reports whether there
+// was one.
+func (c *headerChain) read() bool {
+	if c.chain == nil {
+		return false
+	}
+	d, err := c.chain.Next()
+	var h TiffHeader
+	if err == nil {
+		h, err = headerFromDirectory(d)
 	}
-	numEntries := int(bo.Uint16(entryCountRaw))
-	entriesRaw, err := read(ifdOffset+2, numEntries*12)
 	if err != nil {
-		return TiffHeader{}, err
+		if err != io.EOF {
+			c.err = err
+		}
+		c.chain = nil
+		return false
 	}
+	c.headers = append(c.headers, h)
+	return true
+}
 
-	hdr := TiffHeader{
-		ByteOrder:       bo,
-		BitsPerSample:   nil,
-		SamplesPerPixel: -1,
-		Photometric:     photometric.Unknown,
-		Compression:     compression.Unknown,
-		PlanarConfig:    planarconfig.Unknown,
-	}
-
-	for i := 0; i < numEntries; i++ {
-		entry := entriesRaw[i*12 : (i+1)*12]
-		tag := tifftag.Tag(bo.Uint16(entry[0:2]))
-		// typ := bo.Uint16(entry[2:4])
-		count := bo.Uint32(entry[4:8])
-		valOffset := int64(bo.Uint32(entry[8:12]))
-
-		readShortArray := func() ([]int, error) {
-			if count == 1 {
-				return []int{int(bo.Uint16(entry[8:10]))}, nil
-			}
-			buf, err := read(valOffset, int(count*2))
-			if err != nil {
-				return nil, err
-			}
-			out := make([]int, count)
-			for i := uint32(0); i < count; i++ {
-				out[i] = int(bo.Uint16(buf[i*2:]))
-			}
-			return out, nil
+// selectDirectory reads directories up to the one holding the given page
+// and overview, as numbered by SelectDirectory, and returns its index.
+func (c *headerChain) selectDirectory(page, overview int) (int, error) {
+	for {
+		i, err := SelectDirectory(c.headers, page, overview)
+		if err == nil {
+			return i, nil
 		}
-		readLongArray := func() ([]int, error) {
-			if count == 1 {
-				return []int{int(valOffset)}, nil
-			}
-			buf, err := read(valOffset, int(count*4))
-			if err != nil {
-				return nil, err
-			}
-			out := make([]int, count)
-			for i := uint32(0); i < count; i++ {
-				out[i] = int(bo.Uint32(buf[i*4:]))
+		if !c.read() {
+			if c.err != nil {
+				return 0, c.err
 			}
-			return out, nil
+			return 0, err
 		}
+	}
+}
 
-		switch tag {
-		case tifftag.ImageWidth:
-			hdr.Width = int(valOffset)
-		case tifftag.ImageLength:
-			hdr.Height = int(valOffset)
-		case tifftag.BitsPerSample:
-			hdr.BitsPerSample, err = readShortArray()
-			if err != nil {
-				return TiffHeader{}, err
-			}
-		case tifftag.Compression:
-			hdr.Compression = compression.Type(bo.Uint16(entry[8:10]))
-		case tifftag.PhotometricInterpretation:
-			hdr.Photometric = photometric.Interpretation(bo.Uint16(entry[8:10]))
-		case tifftag.StripOffsets:
-			hdr.StripOffsets, err = readLongArray()
-			if err != nil {
-				return TiffHeader{}, err
-			}
-		case tifftag.SamplesPerPixel:
-			hdr.SamplesPerPixel = int(bo.Uint16(entry[8:10]))
-		case tifftag.RowsPerStrip:
-			hdr.RowsPerStrip = int(valOffset)
-		case tifftag.StripByteCounts:
-			hdr.StripByteCounts, err = readLongArray()
-			if err != nil {
-				return TiffHeader{}, err
-			}
-		case tifftag.PlanarConfiguration:
-			hdr.PlanarConfig = planarconfig.Type(bo.Uint16(entry[8:10]))
-		case tifftag.TileWidth:
-			hdr.TileWidth = int(valOffset)
-		case tifftag.TileLength:
-			hdr.TileHeight = int(valOffset)
-		case tifftag.TileOffsets:
-			hdr.TileOffsets, err = readLongArray()
-			if err != nil {
-				return TiffHeader{}, err
-			}
-		case tifftag.TileByteCounts:
-			hdr.TileByteCounts, err = readLongArray()
-			if err != nil {
-				return TiffHeader{}, err
-			}
+// readMasks reads the directories directly following headers[index] for as
+// long as they are transparency masks. Masks are optional, so a directory
+// that cannot be read just ends the search.
+func (c *headerChain) readMasks(index int) {
+	for i := index + 1; ; i++ {
+		if i >= len(c.headers) && !c.read() {
+			return
 		}
+		if !c.headers[i].SubfileType.Has(subfile.Mask) {
+			return
+		}
+	}
+}
+
+// headerFromDirectory extracts the layout, compression and format fields
+// from a parsed directory. Missing tags take the defaults defined by the TIFF
+// specification; tags without a spec default keep their "unknown" values.
+//
+// SamplesPerPixel sizes per-sample arrays, so values outside the range of
+// its SHORT field type are rejected as ifd.ErrInvalidDirectory.
+func headerFromDirectory(d *ifd.Directory) (TiffHeader, error) {
+	// scalar returns the first value of an integer tag. Absent tags resolve
+	// to their TIFF-spec default, or to def if the spec defines none.
+	scalar := func(tag tifftag.Tag, def int) int {
+		if v, ok := d.Uint(tag); ok {
+			return int(v)
+		}
+		if info, ok := tifftag.Lookup(tag); ok && len(info.Default) > 0 {
+			return int(info.Default[0])
+		}
+		return def
+	}
+	// array returns the values of an integer tag, or nil if absent.
+	array := func(tag tifftag.Tag) []int {
+		vs, ok := d.Uints(tag)
+		if !ok {
+			return nil
+		}
+		out := make([]int, len(vs))
+		for i, v := range vs {
+			out[i] = int(v)
+		}
+		return out
+	}
+
+	hdr := TiffHeader{
+		Directory:       d,
+		ByteOrder:       d.ByteOrder,
+		SubfileType:     subfile.Type(scalar(tifftag.NewSubfileType, 0)),
+		Width:           scalar(tifftag.ImageWidth, 0),
+		Height:          scalar(tifftag.ImageLength, 0),
+		SamplesPerPixel: scalar(tifftag.SamplesPerPixel, 1),
+		Photometric:     photometric.Interpretation(scalar(tifftag.PhotometricInterpretation, int(photometric.Unknown))),
+		Compression:     compression.Type(scalar(tifftag.Compression, int(compression.Unknown))),
+		PlanarConfig:    planarconfig.Type(scalar(tifftag.PlanarConfiguration, int(planarconfig.Unknown))),
+		Orientation:     orientation.Type(scalar(tifftag.Orientation, int(orientation.TopLeft))),
+		RowsPerStrip:    scalar(tifftag.RowsPerStrip, 0),
+		TileWidth:       scalar(tifftag.TileWidth, 0),
+		TileHeight:      scalar(tifftag.TileLength, 0),
+	}
+
+	if hdr.SamplesPerPixel < 1 || hdr.SamplesPerPixel > maxSamplesPerPixel {
+		return TiffHeader{}, fmt.Errorf("%w: %d samples per pixel", ifd.ErrInvalidDirectory, hdr.SamplesPerPixel)
+	}
+
+	// BitsPerSample defaults to 1 for every sample; a single value written
+	// for a multi-sample image applies to all samples.
+	hdr.BitsPerSample = array(tifftag.BitsPerSample)
+	if len(hdr.BitsPerSample) < hdr.SamplesPerPixel {
+		bits := scalar(tifftag.BitsPerSample, 1)
+		hdr.BitsPerSample = make([]int, hdr.SamplesPerPixel)
+		for i := range hdr.BitsPerSample {
+			hdr.BitsPerSample[i] = bits
+		}
+	}
+
+	// RowsPerStrip defaults to 2**32-1, i.e. the whole image is one strip.
+	if hdr.RowsPerStrip <= 0 || hdr.RowsPerStrip > hdr.Height {
+		hdr.RowsPerStrip = hdr.Height
+	}
+	hdr.StripOffsets = array(tifftag.StripOffsets)
+	hdr.StripByteCounts = array(tifftag.StripByteCounts)
+	hdr.TileOffsets = array(tifftag.TileOffsets)
+	hdr.TileByteCounts = array(tifftag.TileByteCounts)
+	for _, v := range array(tifftag.ExtraSamples) {
+		hdr.ExtraSamples = append(hdr.ExtraSamples, extrasample.Type(v))
 	}
 
 	return hdr, nil
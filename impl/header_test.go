@@ -0,0 +1,131 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/echoflaresat/tiff/compression"
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/orientation"
+	"github.com/echoflaresat/tiff/planarconfig"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestHeaderDefaults(t *testing.T) {
+	// Only the tags without a specification default.
+	data := tifftest.Build(tifftest.IFD{
+		Entries: []tifftest.Entry{
+			tifftest.Short(tifftag.ImageWidth, 3),
+			tifftest.Short(tifftag.ImageLength, 2),
+			tifftest.Short(tifftag.PhotometricInterpretation, 1),
+		},
+		Blocks: [][]byte{{0}},
+	})
+	h, err := parseTiffHeader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Compression != compression.None || h.PlanarConfig != planarconfig.Contig || h.Orientation != orientation.TopLeft {
+		t.Errorf("Compression %v, PlanarConfig %v, Orientation %v", h.Compression, h.PlanarConfig, h.Orientation)
+	}
+	if h.SamplesPerPixel != 1 || !slices.Equal(h.BitsPerSample, []int{1}) {
+		t.Errorf("SamplesPerPixel %d, BitsPerSample %v", h.SamplesPerPixel, h.BitsPerSample)
+	}
+	if h.RowsPerStrip != 2 {
+		t.Errorf("RowsPerStrip = %d, want the image height", h.RowsPerStrip)
+	}
+	// A 1-bit BlackIsZero image is supported with the defaults.
+	if !Lazy(h) {
+		t.Errorf("Lazy() = false for a default 1-bit image")
+	}
+}
+
+func TestHeaderBitsPerSampleReplicated(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Image(1, 1, 2, 3), tifftest.Short(tifftag.BitsPerSample, 8)),
+		Blocks:  [][]byte{{0, 0, 0}},
+	})
+	h, err := parseTiffHeader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(h.BitsPerSample, []int{8, 8, 8}) {
+		t.Errorf("BitsPerSample = %v, want one value per sample", h.BitsPerSample)
+	}
+	if h.RowsPerStrip != 1 {
+		t.Errorf("RowsPerStrip = %d", h.RowsPerStrip)
+	}
+}
+
+func TestStrictPlanarConfig(t *testing.T) {
+	for _, tt := range []struct {
+		planar  uint64
+		wantErr bool
+	}{
+		{1, false},
+		{2, false},
+		{0, true},
+		{3, true},
+	} {
+		data := tifftest.Build(tifftest.IFD{
+			Entries: tifftest.With(tifftest.Gray(1, 1), tifftest.Short(tifftag.PlanarConfiguration, tt.planar)),
+			Blocks:  [][]byte{{0}},
+		})
+		_, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{Strict: true, CacheBytes: 1 << 20})
+		if tt.wantErr != errors.Is(err, ifd.ErrInvalidDirectory) {
+			t.Errorf("PlanarConfiguration %d: Load() = %v, wantErr %v", tt.planar, err, tt.wantErr)
+		}
+		h, err := parseTiffHeader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Lazy(h) == tt.wantErr {
+			t.Errorf("PlanarConfiguration %d: Lazy() = %v", tt.planar, Lazy(h))
+		}
+	}
+}
+
+func TestStrictRequiredTags(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: []tifftest.Entry{
+			tifftest.Short(tifftag.ImageWidth, 1),
+			tifftest.Short(tifftag.ImageLength, 1),
+		},
+		Blocks: [][]byte{{0}},
+	})
+	if _, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{Strict: true}); !errors.Is(err, ifd.ErrInvalidDirectory) {
+		t.Errorf("Load() = %v, want missing PhotometricInterpretation", err)
+	}
+	if _, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20}); errors.Is(err, ifd.ErrInvalidDirectory) {
+		t.Errorf("lenient Load() = %v, want no validation error", err)
+	}
+}
+
+func TestHeaderSamplesPerPixelOutOfRange(t *testing.T) {
+	for _, samples := range []uint64{0, 1 << 16, 1 << 40} {
+		entries := tifftest.With(tifftest.Gray(1, 1), tifftest.Entry{Tag: tifftag.SamplesPerPixel, Type: fieldtype.Long8, Values: []uint64{samples}})
+		data := tifftest.File{BigTIFF: true, IFDs: []tifftest.IFD{{Entries: entries, Blocks: [][]byte{{0}}}}}.Bytes()
+		if _, err := parseTiffHeader(bytes.NewReader(data)); !errors.Is(err, ifd.ErrInvalidDirectory) {
+			t.Errorf("SamplesPerPixel %d: parseTiffHeader() = %v, want ErrInvalidDirectory", samples, err)
+		}
+		if _, err := ReadHeaders(bytes.NewReader(data)); !errors.Is(err, ifd.ErrInvalidDirectory) {
+			t.Errorf("SamplesPerPixel %d: ReadHeaders() = %v, want ErrInvalidDirectory", samples, err)
+		}
+	}
+
+	// A trailing directory with an invalid value ends the chain.
+	bad := tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(1, 1), tifftest.Long(tifftag.SamplesPerPixel, 1<<31)),
+		Blocks:  [][]byte{{0}},
+	}
+	data := tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(1, 1), Blocks: [][]byte{{7}}}, bad)
+	headers, err := ReadHeaders(bytes.NewReader(data))
+	if err != nil || len(headers) != 1 {
+		t.Errorf("ReadHeaders() = %d headers, %v, want the first one", len(headers), err)
+	}
+}
@@ -0,0 +1,138 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file implements the image.Image behavior shared by the striped and tiled loaders.
+package impl
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/echoflaresat/tiff/blockcache"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/orientation"
+)
+
+// lazyImage implements image.Image and the band accessors on top of a
+// per-pixel sample lookup provided by the striped or tiled loader.
+//
+// Views derived from a lazyImage (for example with a different band-to-RGB
+// mapping) share the same loader and therefore the same block cache.
+type lazyImage struct {
+	reader io.ReaderAt
+	size   int64 // size of reader, or -1 if unknown
+	header TiffHeader
+	format pixelFormat
+
+	// cache holds the decoded blocks of the directory.
+	cache *blockcache.Handle
+
+	// pixel returns the raw samples of the pixel at (x, y), reading the
+	// underlying block on behalf of ctx. The returned slice must not be modified.
+	pixel func(ctx context.Context, x, y int) ([]byte, error)
+
+	// blocks returns the cached blocks covering r, given in stored
+	// coordinates, for coalesced reads.
+	blocks func(r image.Rectangle) []blockRange
+
+	// coalesceGap and coalesceMaxBytes configure the merging of block
+	// reads; coalescing is disabled if coalesceMaxBytes is 0.
+	coalesceGap, coalesceMaxBytes int64
+
+	// state is shared with all views of the image.
+	state *imageState
+
+	// owned is closed together with the image, or nil.
+	owned io.Closer
+
+	// mask is the transparency mask subfile applied to alpha, or nil.
+	mask *lazyImage
+
+	// orientation is the display orientation pixel coordinates are given in.
+	// The zero value presents the stored layout.
+	orientation orientation.Type
+
+	// sub restricts the bounds of views created by SubImage, or is nil.
+	sub *image.Rectangle
+
+	// nodata is rendered as transparent when hasNoData is set.
+	nodata    byte
+	hasNoData bool
+}
+
+// ColorModel returns the color model of the image.
+// It is color.NRGBAModel for unassociated alpha and color.RGBAModel otherwise.
+func (l *lazyImage) ColorModel() color.Model {
+	return l.format.colorModel()
+}
+
+// Bounds returns the image rectangle in display orientation.
+func (l *lazyImage) Bounds() image.Rectangle {
+	if l.sub != nil {
+		return *l.sub
+	}
+	w, h := l.orientation.Size(l.header.Width, l.header.Height)
+	return image.Rect(0, 0, w, h)
+}
+
+// At returns the color of the pixel at (x, y).
+// The underlying strip or tile is read on demand; I/O errors and reads after
+// Close cause a panic because image.Image offers no way to report them.
+func (l *lazyImage) At(x, y int) color.Color {
+	c, err := l.AtContext(context.Background(), x, y)
+	if err != nil {
+		panic(err.Error())
+	}
+	return c
+}
+
+// AtContext returns the color of the pixel at (x, y) like At, reading the
+// underlying strip or tile on behalf of ctx and reporting I/O errors and
+// cancellation instead of panicking.
+func (l *lazyImage) AtContext(ctx context.Context, x, y int) (color.Color, error) {
+	if !(image.Point{X: x, Y: y}.In(l.Bounds())) {
+		return l.format.zero(), nil
+	}
+	end, err := l.state.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	hidden, err := l.masked(ctx, x, y)
+	if err != nil {
+		return nil, err
+	}
+	if hidden {
+		return l.format.zero(), nil
+	}
+	px, err := l.pixel(ctx, x, y)
+	if err != nil {
+		return nil, err
+	}
+	if l.isNoData(px) {
+		return l.format.zero(), nil
+	}
+	return l.format.color(px), nil
+}
+
+// SubImage returns a lazy view of the part of the image visible through r.
+// Like the standard library images, the view keeps the coordinates of l and
+// shares its block cache; no pixel data is read.
+func (l *lazyImage) SubImage(r image.Rectangle) image.Image {
+	r = r.Intersect(l.Bounds())
+	view := *l
+	view.sub = &r
+	return &view
+}
+
+// Directory returns the parsed IFD of the image, giving typed access to every
+// tag it contains, including unknown and private ones.
+func (l *lazyImage) Directory() *ifd.Directory {
+	return l.header.Directory
+}
+
+// CacheStats returns the block cache statistics of the image. Views of the
+// image share its statistics; the transparency mask is accounted separately.
+func (l *lazyImage) CacheStats() blockcache.Stats {
+	return l.cache.Stats()
+}
@@ -0,0 +1,161 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file selects and loads a directory according to the decode options.
+package impl
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/echoflaresat/tiff/blockcache"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/planarconfig"
+	"github.com/echoflaresat/tiff/subfile"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// LoadOptions configures Load.
+type LoadOptions struct {
+	// Page selects the full-resolution image, counting from 0.
+	Page int
+
+	// Overview selects a reduced-resolution image of the page, counting
+	// from 1 in file order; 0 selects the full-resolution image.
+	Overview int
+
+	// Cache holds the decoded strips or tiles of the image. If nil, a
+	// private cache of CacheBytes is used, or blockcache.Default if
+	// CacheBytes is 0.
+	Cache      *blockcache.Cache
+	CacheBytes int64
+
+	// Strict rejects directories that violate the TIFF specification, such
+	// as unsorted entries, unexpected field types or counts and missing
+	// required tags, instead of tolerating them.
+	Strict bool
+
+	// CoalesceGap and CoalesceMaxBytes control how ReadRegion merges the
+	// byte ranges of uncached strips and tiles: blocks at most CoalesceGap
+	// bytes apart are fetched with a single read of up to CoalesceMaxBytes.
+	// 0 selects DefaultCoalesceGap and DefaultCoalesceMaxBytes; a negative
+	// CoalesceGap disables merging.
+	CoalesceGap      int64
+	CoalesceMaxBytes int64
+
+	// ReadAhead enables sequential access: whenever a strip or tile is
+	// read, the ReadAhead blocks following it in the file are loaded into
+	// the cache in the background. 0 disables read-ahead.
+	ReadAhead int
+
+	// Owned is closed by the Close method of the returned image, e.g. a
+	// temporary file the reader was spooled to. Load does not close it if
+	// it fails.
+	Owned io.Closer
+}
+
+// Load parses the directories of reader and returns a lazy image of the
+// directory selected by opts, with its transparency mask applied.
+// The directories are read on behalf of ctx; pixel data is read later on
+// behalf of the contexts given to the image methods.
+func Load(ctx context.Context, reader io.ReaderAt, opts LoadOptions) (image.Image, error) {
+	chain, err := newHeaderChain(WithContext(ctx, reader))
+	if err != nil {
+		return nil, err
+	}
+	index, err := chain.selectDirectory(opts.Page, opts.Overview)
+	if err != nil {
+		return nil, err
+	}
+	chain.readMasks(index)
+	headers := chain.headers
+	if opts.Strict {
+		if err := validate(headers[index]); err != nil {
+			return nil, fmt.Errorf("directory %d: %w", index, err)
+		}
+	}
+
+	cache := opts.Cache
+	switch {
+	case cache != nil:
+	case opts.CacheBytes > 0:
+		cache = blockcache.New(opts.CacheBytes)
+	default:
+		cache = blockcache.Default
+	}
+
+	l, err := loadDirectory(reader, headers[index], cache.Handle(blockcache.NewFileID(), index))
+	if err != nil {
+		return nil, err
+	}
+	l.owned = opts.Owned
+	l.state.readAhead = max(opts.ReadAhead, 0)
+	if opts.CoalesceGap >= 0 {
+		l.coalesceGap = opts.CoalesceGap
+		if l.coalesceGap == 0 {
+			l.coalesceGap = DefaultCoalesceGap
+		}
+		l.coalesceMaxBytes = opts.CoalesceMaxBytes
+		if l.coalesceMaxBytes <= 0 {
+			l.coalesceMaxBytes = DefaultCoalesceMaxBytes
+		}
+	}
+	l.attachMask(reader, headers, index, cache)
+	return l, nil
+}
+
+// SelectDirectory returns the index of the directory holding the given page
+// and overview.
+//
+// Every directory that is neither a reduced-resolution image nor a mask
+// starts a page; the reduced-resolution images following it, up to the next
+// page, are its overviews.
+func SelectDirectory(headers []TiffHeader, page, overview int) (int, error) {
+	if page < 0 || overview < 0 {
+		return 0, fmt.Errorf("invalid page %d, overview %d", page, overview)
+	}
+	p, o := -1, 0
+	for i, h := range headers {
+		switch {
+		case h.SubfileType.Has(subfile.Mask):
+			continue
+		case h.SubfileType.Has(subfile.ReducedImage):
+			if p < 0 {
+				continue
+			}
+			o++
+		default:
+			p, o = p+1, 0
+		}
+		if p == page && o == overview {
+			return i, nil
+		}
+	}
+	if p < page {
+		return 0, fmt.Errorf("page %d not found: file has %d pages", page, p+1)
+	}
+	return 0, fmt.Errorf("overview %d of page %d not found", overview, page)
+}
+
+// validate checks the directory of h against the TIFF specification and
+// reports required tags that are missing and invalid planar configurations.
+func validate(h TiffHeader) error {
+	if err := h.Directory.Validate(); err != nil {
+		return err
+	}
+	if h.PlanarConfig != planarconfig.Contig && h.PlanarConfig != planarconfig.Separate {
+		return fmt.Errorf("%w: invalid %s %d", ifd.ErrInvalidDirectory, tifftag.PlanarConfiguration, h.PlanarConfig)
+	}
+	required := []tifftag.Tag{tifftag.ImageWidth, tifftag.ImageLength, tifftag.PhotometricInterpretation}
+	if h.Directory.Has(tifftag.TileOffsets) {
+		required = append(required, tifftag.TileWidth, tifftag.TileLength, tifftag.TileByteCounts)
+	} else {
+		required = append(required, tifftag.StripOffsets, tifftag.StripByteCounts)
+	}
+	for _, tag := range required {
+		if !h.Directory.Has(tag) {
+			return fmt.Errorf("%w: missing required tag %s", ifd.ErrInvalidDirectory, tag)
+		}
+	}
+	return nil
+}
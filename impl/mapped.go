@@ -0,0 +1,37 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file gives zero-copy access to readers backed by memory.
+package impl
+
+import (
+	"io"
+
+	"github.com/echoflaresat/tiff/compression"
+)
+
+// mappedReader is implemented by readers whose contents are held in memory,
+// such as memory-mapped files. Bytes returns nil if the contents are not
+// available, in which case the reader is accessed through ReadAt.
+type mappedReader interface {
+	Bytes() []byte
+}
+
+// mapped returns the n bytes at off of r without copying them, if r is
+// backed by memory and holds the whole range.
+func mapped(r io.ReaderAt, off, n int64) ([]byte, bool) {
+	m, ok := r.(mappedReader)
+	if !ok {
+		return nil, false
+	}
+	data := m.Bytes()
+	if data == nil || off < 0 || n < 0 || off > int64(len(data))-n {
+		return nil, false
+	}
+	return data[off : off+n : off+n], true
+}
+
+// inPlace reports whether the blocks of l are read in place from a
+// memory-backed reader, bypassing the cache.
+func (l *lazyImage) inPlace() bool {
+	_, ok := mapped(l.reader, 0, 0)
+	return ok && l.header.Compression == compression.None
+}
@@ -0,0 +1,92 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file implements transparency masks stored as separate subfiles.
+package impl
+
+import (
+	"context"
+	"image"
+	"io"
+
+	"github.com/echoflaresat/tiff/blockcache"
+	"github.com/echoflaresat/tiff/subfile"
+)
+
+// findMask returns the index of the transparency mask directory associated
+// with headers[index], or -1 if there is none.
+//
+// A mask is a directory with the NewSubfileType mask bit set (as written by
+// GDAL for internal masks and by libtiff for TransMask images) among those
+// directly following the image, with the same reduced-resolution bit and
+// dimensions as the image.
+func findMask(headers []TiffHeader, index int) int {
+	img := headers[index]
+	if img.SubfileType.Has(subfile.Mask) {
+		return -1
+	}
+	reduced := img.SubfileType.Has(subfile.ReducedImage)
+	for i := index + 1; i < len(headers) && headers[i].SubfileType.Has(subfile.Mask); i++ {
+		h := headers[i]
+		if h.SubfileType.Has(subfile.ReducedImage) == reduced && h.Width == img.Width && h.Height == img.Height {
+			return i
+		}
+	}
+	return -1
+}
+
+// loadDirectory returns the lazy image for a single directory, using the
+// striped or tiled loader depending on the layout the directory declares.
+func loadDirectory(reader io.ReaderAt, header TiffHeader, cache *blockcache.Handle) (*lazyImage, error) {
+	if len(header.TileOffsets) > 0 {
+		t, err := newTiledTiff(reader, header, cache)
+		if err != nil {
+			return nil, err
+		}
+		return t.lazyImage, nil
+	}
+	t, err := newStripedTiff(reader, header, cache)
+	if err != nil {
+		return nil, err
+	}
+	return t.lazyImage, nil
+}
+
+// attachMask looks up the transparency mask of headers[index] and, if present,
+// applies it to l. Pixels where the mask is zero become fully transparent.
+// The mask caches its decoded blocks in cache, in the same file as l.
+//
+// Masks the lazy loaders cannot decode are ignored, leaving the image
+// opaque rather than failing to load it.
+func (l *lazyImage) attachMask(reader io.ReaderAt, headers []TiffHeader, index int, cache *blockcache.Cache) {
+	i := findMask(headers, index)
+	if i < 0 {
+		return
+	}
+	mask, err := loadDirectory(reader, headers[i], cache.Handle(l.cache.File(), i))
+	if err != nil || mask.format.samples != 1 {
+		return
+	}
+	l.mask = mask
+}
+
+// Mask returns the transparency mask applied to the image as a lazy image
+// in the RGBA color model whose pixels are opaque black where the image is
+// transparent and opaque white where it is visible, or nil if the image has
+// no mask.
+func (l *lazyImage) Mask() image.Image {
+	if l.mask == nil {
+		return nil
+	}
+	return l.mask
+}
+
+// masked reports whether the mask hides the pixel at (x, y).
+func (l *lazyImage) masked(ctx context.Context, x, y int) (bool, error) {
+	if l.mask == nil {
+		return false, nil
+	}
+	m, err := l.mask.pixel(ctx, x, y)
+	if err != nil {
+		return false, err
+	}
+	return m[0] == 0, nil
+}
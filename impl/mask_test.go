@@ -0,0 +1,127 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// maskIFD returns a 1-bit TransMask directory of 8 × 2 pixels with the given
+// NewSubfileType, hiding the left half of the image.
+func maskIFD(subfileType uint64) tifftest.IFD {
+	return tifftest.IFD{
+		Entries: tifftest.With(tifftest.Image(8, 2, 4, 1),
+			tifftest.Short(tifftag.BitsPerSample, 1),
+			tifftest.Long(tifftag.NewSubfileType, subfileType),
+			tifftest.Short(tifftag.RowsPerStrip, 2)),
+		Blocks: [][]byte{{0x0f, 0x0f}},
+	}
+}
+
+// grayIFD returns an 8-bit grayscale directory of w × h pixels with the given
+// NewSubfileType, filled with v.
+func grayIFD(w, h int, subfileType uint64, v byte) tifftest.IFD {
+	return tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(w, h), tifftest.Long(tifftag.NewSubfileType, subfileType)),
+		Blocks:  [][]byte{bytes.Repeat([]byte{v}, w*h)},
+	}
+}
+
+func TestLoadMask(t *testing.T) {
+	opaque := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	tests := []struct {
+		name   string
+		ifds   []tifftest.IFD
+		opts   LoadOptions
+		masked bool
+	}{
+		{"no mask", []tifftest.IFD{grayIFD(8, 2, 0, 0x80)}, LoadOptions{}, false},
+		{"mask", []tifftest.IFD{grayIFD(8, 2, 0, 0x80), maskIFD(4)}, LoadOptions{}, true},
+		{
+			"overview mask",
+			[]tifftest.IFD{grayIFD(16, 4, 0, 0x80), maskIFD(4 | 1), grayIFD(8, 2, 1, 0x80), maskIFD(4 | 1)},
+			LoadOptions{Overview: 1},
+			true,
+		},
+		{
+			"mask of overview not applied to image",
+			[]tifftest.IFD{grayIFD(8, 2, 0, 0x80), maskIFD(4 | 1)},
+			LoadOptions{},
+			false,
+		},
+		{
+			"mask not directly following",
+			[]tifftest.IFD{grayIFD(8, 2, 0, 0x80), grayIFD(8, 2, 0, 0x80), maskIFD(4)},
+			LoadOptions{},
+			false,
+		},
+		{
+			"unsupported mask ignored",
+			[]tifftest.IFD{grayIFD(8, 2, 0, 0x80), func() tifftest.IFD {
+				d := maskIFD(4)
+				d.Entries = tifftest.With(d.Entries, tifftest.Short(tifftag.Compression, 8))
+				return d
+			}()},
+			LoadOptions{},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.CacheBytes = 1 << 20
+			img, err := Load(context.Background(), bytes.NewReader(tifftest.Build(tt.ifds...)), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l := img.(*lazyImage)
+			if got := l.Mask() != nil; got != tt.masked {
+				t.Fatalf("Mask() != nil = %v, want %v", got, tt.masked)
+			}
+			want := opaque
+			if tt.masked {
+				want = color.RGBA{}
+			}
+			if got := img.At(0, 0); got != want {
+				t.Errorf("At(0, 0) = %v, want %v", got, want)
+			}
+			if got := img.At(7, 1); got != opaque {
+				t.Errorf("At(7, 1) = %v, want %v", got, opaque)
+			}
+		})
+	}
+}
+
+func TestLoadCorruptTrailingDirectory(t *testing.T) {
+	first := grayIFD(4, 4, 0, 0x40)
+	first.Next = 1 << 20 // past the end of the file
+	data := tifftest.Build(first)
+
+	for _, loader := range []struct {
+		name string
+		load func() (image.Image, error)
+	}{
+		{"Load", func() (image.Image, error) {
+			return Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+		}},
+		{"LoadStripedTiff", func() (image.Image, error) { return LoadStripedTiff(bytes.NewReader(data)) }},
+	} {
+		t.Run(loader.name, func(t *testing.T) {
+			img, err := loader.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := img.At(1, 1), (color.RGBA{0x40, 0x40, 0x40, 0xff}); got != want {
+				t.Errorf("At(1, 1) = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if _, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{Page: 1}); err == nil {
+		t.Errorf("Load(page 1) succeeded, want error for the corrupt directory")
+	}
+}
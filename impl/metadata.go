@@ -0,0 +1,61 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file exposes metadata stored in sub-directories and private tags.
+package impl
+
+import (
+	"github.com/echoflaresat/tiff/exif"
+	"github.com/echoflaresat/tiff/icc"
+	"github.com/echoflaresat/tiff/iptc"
+	"github.com/echoflaresat/tiff/xmp"
+)
+
+// Exif follows the EXIF IFD pointer of the image and parses the camera metadata.
+// It returns exif.ErrNotFound if the image has no EXIF IFD, and ErrClosed
+// once the image has been closed.
+func (l *lazyImage) Exif() (*exif.Exif, error) {
+	end, err := l.state.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return exif.ReadExif(l.reader, l.header.Directory)
+}
+
+// GPS follows the GPS IFD pointer of the image and parses the position metadata.
+// It returns exif.ErrNotFound if the image has no GPS IFD, and ErrClosed
+// once the image has been closed.
+func (l *lazyImage) GPS() (*exif.GPS, error) {
+	end, err := l.state.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return exif.ReadGPS(l.reader, l.header.Directory)
+}
+
+// ICCProfile returns the embedded ICC profile with its decoded header and description.
+// It returns icc.ErrNotFound if the image has none.
+func (l *lazyImage) ICCProfile() (*icc.Profile, error) {
+	if err := l.checkOpen(); err != nil {
+		return nil, err
+	}
+	return icc.FromDirectory(l.header.Directory)
+}
+
+// XMP returns the embedded XMP packet with its simple properties.
+// It returns xmp.ErrNotFound if the image has none.
+func (l *lazyImage) XMP() (*xmp.Packet, error) {
+	if err := l.checkOpen(); err != nil {
+		return nil, err
+	}
+	return xmp.FromDirectory(l.header.Directory)
+}
+
+// IPTC returns the embedded IPTC/NAA block decoded into datasets.
+// It returns iptc.ErrNotFound if the image has none.
+func (l *lazyImage) IPTC() (*iptc.Block, error) {
+	if err := l.checkOpen(); err != nil {
+		return nil, err
+	}
+	return iptc.FromDirectory(l.header.Directory)
+}
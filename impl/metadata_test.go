@@ -0,0 +1,63 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/echoflaresat/tiff/exif"
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/icc"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/iptc"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestMetadata(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(1, 1),
+			tifftest.Entry{Tag: tifftag.XMP, Type: fieldtype.Byte, Raw: []byte(`<rdf:Description xmlns:rdf="r" xmlns:dc="d" dc:format="image/tiff"/>`)},
+			tifftest.Undefined(tifftag.IPTCNAA, []byte{0x1c, 2, 5, 0, 1, 'x'})),
+		Blocks: [][]byte{{0}},
+		SubIFDs: map[tifftag.Tag]tifftest.IFD{
+			tifftag.ExifIFD: {Entries: []tifftest.Entry{tifftest.Short(tifftag.ISOSpeedRatings, 200)}},
+		},
+	})
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+
+	if e, err := l.Exif(); err != nil || e.ISO != 200 {
+		t.Errorf("Exif() = %+v, %v", e, err)
+	}
+	if _, err := l.GPS(); !errors.Is(err, exif.ErrNotFound) {
+		t.Errorf("GPS() = %v, want exif.ErrNotFound", err)
+	}
+	if p, err := l.XMP(); err != nil || p.Properties["dc:format"][0] != "image/tiff" {
+		t.Errorf("XMP() = %+v, %v", p, err)
+	}
+	if b, err := l.IPTC(); err != nil || len(b.Values(2, iptc.ObjectName)) != 1 {
+		t.Errorf("IPTC() = %+v, %v", b, err)
+	}
+	if _, err := l.ICCProfile(); !errors.Is(err, icc.ErrNotFound) {
+		t.Errorf("ICCProfile() = %v, want icc.ErrNotFound", err)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, read := range map[string]func() error{
+		"Exif":       func() error { _, err := l.Exif(); return err },
+		"GPS":        func() error { _, err := l.GPS(); return err },
+		"XMP":        func() error { _, err := l.XMP(); return err },
+		"IPTC":       func() error { _, err := l.IPTC(); return err },
+		"ICCProfile": func() error { _, err := l.ICCProfile(); return err },
+	} {
+		if err := read(); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s() after Close = %v, want ErrClosed", name, err)
+		}
+	}
+}
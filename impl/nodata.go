@@ -0,0 +1,61 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file implements GDAL nodata handling for lazy images.
+package impl
+
+import (
+	"image"
+	"math"
+
+	"github.com/echoflaresat/tiff/gdal"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// GDALMetadata parses the GDAL_NODATA and GDAL_METADATA tags of the image,
+// returning the nodata value and per-band scale, offset, description and statistics.
+func (l *lazyImage) GDALMetadata() (*gdal.Metadata, error) {
+	return gdal.Parse(l.header.Directory, l.format.samples)
+}
+
+// MaskNoData returns img rendering pixels whose displayed bands all equal the
+// GDAL nodata value as fully transparent. Images without a GDAL_NODATA tag,
+// with a nodata value that 8-bit samples cannot hold, or not produced by the
+// lazy loaders are returned unchanged.
+func MaskNoData(img image.Image) image.Image {
+	l, ok := img.(interface {
+		maskNoData() *lazyImage
+	})
+	if !ok {
+		return img
+	}
+	return l.maskNoData()
+}
+
+// maskNoData returns a view of l with nodata transparency enabled, or l itself
+// if the image has no usable nodata value.
+func (l *lazyImage) maskNoData() *lazyImage {
+	s, ok := l.header.Directory.ASCII(tifftag.GDALNoData)
+	if !ok {
+		return l
+	}
+	v, err := gdal.ParseNoData(s)
+	if err != nil || v != math.Trunc(v) || v < 0 || v > math.MaxUint8 {
+		return l
+	}
+	view := *l
+	view.nodata = byte(v)
+	view.hasNoData = true
+	return &view
+}
+
+// isNoData reports whether all displayed bands of px equal the nodata value.
+func (l *lazyImage) isNoData(px []byte) bool {
+	if !l.hasNoData {
+		return false
+	}
+	for _, band := range l.format.rgb {
+		if px[band] != l.nodata {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package impl
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestMaskNoData(t *testing.T) {
+	load := func(nodata string) *lazyImage {
+		entries := tifftest.Image(2, 1, 2, 3)
+		if nodata != "" {
+			entries = append(entries, tifftest.ASCII(tifftag.GDALNoData, nodata))
+		}
+		data := tifftest.Build(tifftest.IFD{
+			Entries: append(entries, tifftest.Short(tifftag.RowsPerStrip, 1)),
+			Blocks:  [][]byte{{0, 0, 0, 0, 0, 9}},
+		})
+		img, err := LoadStripedTiff(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img.(*stripedTiff).lazyImage
+	}
+
+	l := load("0")
+	if got := l.At(0, 0); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("At(0, 0) without MaskNoData = %v", got)
+	}
+	masked := MaskNoData(l)
+	if got := masked.At(0, 0); got != (color.RGBA{}) {
+		t.Errorf("At(0, 0) = %v, want transparent nodata", got)
+	}
+	if got := masked.At(1, 0); got != (color.RGBA{0, 0, 9, 255}) {
+		t.Errorf("At(1, 0) = %v, want opaque pixel with one band off nodata", got)
+	}
+
+	for _, nodata := range []string{"", "-9999", "0.5", "nan"} {
+		l := load(nodata)
+		if MaskNoData(l) != l {
+			t.Errorf("MaskNoData with nodata %q changed the image", nodata)
+		}
+	}
+
+	m, err := l.GDALMetadata()
+	if err != nil || !m.HasNoData || m.NoData != 0 || len(m.Bands) != 3 {
+		t.Errorf("GDALMetadata() = %+v, %v", m, err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file implements lazy presentation of images in their display orientation.
+package impl
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/echoflaresat/tiff/orientation"
+)
+
+// ReadOrientation returns the Orientation tag of the first directory in reader,
+// or orientation.TopLeft if the tag is absent.
+func ReadOrientation(reader io.ReaderAt) (orientation.Type, error) {
+	header, err := parseTiffHeader(reader)
+	if err != nil {
+		return 0, err
+	}
+	return header.Orientation, nil
+}
+
+// Orient returns img presented in display orientation o.
+//
+// Width and height are swapped for the transposing orientations (5–8) and
+// coordinates are mapped to the stored pixels on every access, so no pixel
+// data is materialized. Images produced by the lazy loaders keep their band,
+// mask and cache behavior; other images are wrapped. TopLeft and invalid
+// orientations return img unchanged.
+func Orient(img image.Image, o orientation.Type) image.Image {
+	if o == orientation.TopLeft || !o.Valid() {
+		return img
+	}
+	if l, ok := img.(interface {
+		orient(orientation.Type) *lazyImage
+	}); ok {
+		return l.orient(o)
+	}
+	return &orientedImage{src: img, orientation: o}
+}
+
+// orient returns a view of l presented in display orientation o.
+// The view shares the loader and cache of l.
+func (l *lazyImage) orient(o orientation.Type) *lazyImage {
+	w, h := l.header.Width, l.header.Height
+	view := *l
+	view.orientation = o
+	view.pixel = func(ctx context.Context, x, y int) ([]byte, error) {
+		sx, sy := o.ToStored(x, y, w, h)
+		return l.pixel(ctx, sx, sy)
+	}
+	if l.mask != nil {
+		view.mask = l.mask.orient(o)
+	}
+	return &view
+}
+
+// orientedImage presents an arbitrary image.Image in a display orientation.
+type orientedImage struct {
+	src         image.Image
+	orientation orientation.Type
+}
+
+// ColorModel returns the color model of the source image.
+func (o *orientedImage) ColorModel() color.Model {
+	return o.src.ColorModel()
+}
+
+// Bounds returns the displayed bounds, anchored at the source origin.
+func (o *orientedImage) Bounds() image.Rectangle {
+	b := o.src.Bounds()
+	w, h := o.orientation.Size(b.Dx(), b.Dy())
+	return image.Rect(b.Min.X, b.Min.Y, b.Min.X+w, b.Min.Y+h)
+}
+
+// At returns the color of the displayed pixel at (x, y).
+func (o *orientedImage) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(o.Bounds())) {
+		return o.src.ColorModel().Convert(color.Transparent)
+	}
+	b := o.src.Bounds()
+	sx, sy := o.orientation.ToStored(x-b.Min.X, y-b.Min.Y, b.Dx(), b.Dy())
+	return o.src.At(b.Min.X+sx, b.Min.Y+sy)
+}
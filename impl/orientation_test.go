@@ -0,0 +1,85 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/orientation"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestOrient(t *testing.T) {
+	const w, h = 3, 2
+	pix := []byte{1, 2, 3, 4, 5, 6}
+	data := tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(w, h), Blocks: [][]byte{pix}})
+	lazy, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gray := &image.Gray{Pix: pix, Stride: w, Rect: image.Rect(0, 0, w, h)}
+
+	for o := orientation.TopLeft; o <= orientation.LeftBottom; o++ {
+		for name, src := range map[string]image.Image{"lazy": lazy, "wrapped": gray} {
+			img := Orient(src, o)
+			dw, dh := o.Size(w, h)
+			if got := img.Bounds(); got != image.Rect(0, 0, dw, dh) {
+				t.Fatalf("%s %v: Bounds() = %v, want %dx%d", name, o, got, dw, dh)
+			}
+			for y := 0; y < dh; y++ {
+				for x := 0; x < dw; x++ {
+					sx, sy := o.ToStored(x, y, w, h)
+					want := pix[sy*w+sx]
+					if got := color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y; got != want {
+						t.Errorf("%s %v: At(%d, %d) = %d, want %d", name, o, x, y, got, want)
+					}
+				}
+			}
+		}
+	}
+
+	if Orient(lazy, 0) != lazy || Orient(lazy, orientation.TopLeft) != lazy {
+		t.Errorf("Orient with TopLeft or an invalid orientation wrapped the image")
+	}
+}
+
+func TestOrientMaskAndRegion(t *testing.T) {
+	img, err := Load(context.Background(), bytes.NewReader(tifftest.Build(grayIFD(8, 2, 0, 0x80), maskIFD(4))), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rotated 180°, the hidden left half of the mask is on the right.
+	rotated := Orient(img, orientation.BottomRight).(*lazyImage)
+	if got := rotated.At(7, 1); got != (color.RGBA{}) {
+		t.Errorf("At(7, 1) = %v, want transparent", got)
+	}
+	if got, want := rotated.At(0, 0), (color.RGBA{0x80, 0x80, 0x80, 0xff}); got != want {
+		t.Errorf("At(0, 0) = %v, want %v", got, want)
+	}
+
+	dst := make([]byte, 2*8)
+	if err := rotated.ReadRegion(context.Background(), rotated.Bounds(), nil, dst, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dst, bytes.Repeat([]byte{0x80}, 16)) {
+		t.Errorf("ReadRegion = %v, want raw samples regardless of the mask", dst)
+	}
+}
+
+func TestReadOrientation(t *testing.T) {
+	for _, tt := range []struct {
+		entries []tifftest.Entry
+		want    orientation.Type
+	}{
+		{tifftest.Gray(1, 1), orientation.TopLeft},
+		{tifftest.With(tifftest.Gray(1, 1), tifftest.Short(tifftag.Orientation, 6)), orientation.RightTop},
+	} {
+		data := tifftest.Build(tifftest.IFD{Entries: tt.entries, Blocks: [][]byte{{0}}})
+		if got, err := ReadOrientation(bytes.NewReader(data)); err != nil || got != tt.want {
+			t.Errorf("ReadOrientation() = %v, %v, want %v", got, err, tt.want)
+		}
+	}
+}
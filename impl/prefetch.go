@@ -0,0 +1,84 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file loads blocks into the cache ahead of time.
+package impl
+
+import (
+	"context"
+	"image"
+)
+
+// Prefetch starts loading the strips or tiles covering r, and the matching
+// blocks of the transparency mask, into the block cache in the background
+// and returns immediately. Blocks already cached are skipped and nearby
+// blocks are fetched with merged reads, as in ReadRegion. Load errors are
+// not reported; they surface when the pixels are read.
+func (l *lazyImage) Prefetch(r image.Rectangle) {
+	r = r.Intersect(l.Bounds())
+	if r.Empty() || l.blocks == nil || l.inPlace() {
+		return
+	}
+	blocks := l.blocks(l.storedRect(r))
+	l.state.background(func(ctx context.Context) {
+		_ = l.fetchBlocks(ctx, blocks, 0)
+	})
+	if l.mask != nil {
+		l.mask.Prefetch(r)
+	}
+}
+
+// advance schedules the read-ahead of the blocks following block, counted in
+// file order, when sequential access is enabled. The readAhead blocks after
+// the one being read are kept loading in the background; a jump back behind
+// the scheduled window restarts it at block. It is called for every pixel,
+// so pixels of the block read last return right away.
+func (l *lazyImage) advance(block int) {
+	s := l.state
+	if s.readAhead <= 0 || s.current.Load() == int64(block)+1 {
+		return
+	}
+	s.current.Store(int64(block) + 1)
+	if l.blocks == nil || l.inPlace() {
+		return
+	}
+	last := int64(min(block+s.readAhead, l.blockCount()-1))
+	for {
+		prev := s.scheduled.Load()
+		from := prev + 1
+		if int64(block) < prev-int64(2*s.readAhead) {
+			from = int64(block) + 1
+		} else if last <= prev {
+			return
+		}
+		from = max(from, int64(block)+1)
+		if !s.scheduled.CompareAndSwap(prev, last) {
+			continue
+		}
+		if from > last {
+			return
+		}
+		var blocks []blockRange
+		for i := from; i <= last; i++ {
+			blocks = append(blocks, l.blocks(l.blockRect(int(i)))...)
+		}
+		s.background(func(ctx context.Context) {
+			_ = l.fetchBlocks(ctx, blocks, 0)
+		})
+		return
+	}
+}
+
+// blockCount returns the number of strips or tiles of the image.
+func (l *lazyImage) blockCount() int {
+	bw, bh := l.blockSize()
+	return ceilDiv(l.header.Width, bw) * ceilDiv(l.header.Height, bh)
+}
+
+// blockRect returns the stored rectangle covered by block i, counted in
+// file order.
+func (l *lazyImage) blockRect(i int) image.Rectangle {
+	bw, bh := l.blockSize()
+	across := (l.header.Width + bw - 1) / bw
+	bx, by := i%max(across, 1), i/max(across, 1)
+	r := image.Rect(bx*bw, by*bh, (bx+1)*bw, (by+1)*bh)
+	return r.Intersect(image.Rect(0, 0, l.header.Width, l.header.Height))
+}
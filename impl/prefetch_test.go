@@ -0,0 +1,162 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// stripedRows returns a grayscale image of 4 × n pixels with one row per
+// strip.
+func stripedRows(n int) []byte {
+	var strips [][]byte
+	for y := 0; y < n; y++ {
+		strips = append(strips, []byte{byte(y), byte(y), byte(y), byte(y)})
+	}
+	return tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(4, n), tifftest.Short(tifftag.RowsPerStrip, 1)),
+		Blocks:  strips,
+	})
+}
+
+// cached returns the blocks of l that are cached, in order. Tiles are cached
+// by index, rows of strips by rowKey; the strips used here hold one row.
+func cached(l *lazyImage) []int {
+	key := func(i int) uint64 { return uint64(i) }
+	if len(l.header.TileOffsets) == 0 {
+		key = func(i int) uint64 { return rowKey(i, 0) }
+	}
+	var blocks []int
+	for i := 0; i < l.blockCount(); i++ {
+		if l.cache.Contains(key(i)) {
+			blocks = append(blocks, i)
+		}
+	}
+	return blocks
+}
+
+func TestPrefetch(t *testing.T) {
+	data, _ := tiledGray(64, 64)
+	r := &countingReader{Reader: bytes.NewReader(data)}
+	img, err := Load(context.Background(), r, LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	r.reads.Store(0)
+
+	// The right half: tiles 2, 3, 6, 7, ... of a 4 × 4 grid.
+	l.Prefetch(image.Rect(32, 0, 64, 64))
+	l.state.loads.Wait()
+	if got, want := cached(l), []int{2, 3, 6, 7, 10, 11, 14, 15}; !slices.Equal(got, want) {
+		t.Errorf("cached blocks = %v, want %v", got, want)
+	}
+	if n := r.reads.Load(); n != 1 {
+		t.Errorf("Prefetch issued %d reads, want 1 coalesced read", n)
+	}
+
+	// Cached blocks are not fetched again.
+	l.Prefetch(image.Rect(32, 0, 64, 64))
+	l.state.loads.Wait()
+	if n := r.reads.Load(); n != 1 {
+		t.Errorf("second Prefetch issued %d reads, want none", n-1)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l.Prefetch(l.Bounds()) // does nothing after Close
+	if n := l.CacheStats().Blocks; n != 0 {
+		t.Errorf("Prefetch after Close cached %d blocks", n)
+	}
+}
+
+func TestReadAhead(t *testing.T) {
+	img, err := Load(context.Background(), bytes.NewReader(stripedRows(8)), LoadOptions{CacheBytes: 1 << 20, ReadAhead: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	steps := []struct {
+		y    int
+		want []int // rows cached afterwards
+	}{
+		{0, []int{0, 1, 2}},
+		{1, []int{0, 1, 2, 3}},
+		{1, []int{0, 1, 2, 3}},
+		{5, []int{0, 1, 2, 3, 5, 6, 7}},
+	}
+	for _, s := range steps {
+		for x := 0; x < 4; x++ {
+			l.At(x, s.y)
+		}
+		l.state.loads.Wait()
+		if got := cached(l); !slices.Equal(got, s.want) {
+			t.Errorf("after reading row %d: cached rows %v, want %v", s.y, got, s.want)
+		}
+	}
+
+	// Jumping back behind the window restarts it.
+	l.cache.Purge()
+	l.At(0, 0)
+	l.state.loads.Wait()
+	if got, want := cached(l), []int{0, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("after jumping back: cached rows %v, want %v", got, want)
+	}
+}
+
+func TestReadAheadOncePerBlock(t *testing.T) {
+	img, err := Load(context.Background(), bytes.NewReader(stripedRows(8)), LoadOptions{CacheBytes: 1 << 20, ReadAhead: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	l.At(0, 0)
+	l.state.loads.Wait()
+
+	// Further pixels of the same block leave the window alone, even if it
+	// was moved since.
+	l.state.scheduled.Store(-1)
+	l.At(1, 0)
+	if got := l.state.scheduled.Load(); got != -1 {
+		t.Errorf("scheduled = %d after reading the same block, want -1", got)
+	}
+	l.At(0, 1)
+	l.state.loads.Wait()
+	if got := l.state.scheduled.Load(); got != 3 {
+		t.Errorf("scheduled = %d after reading the next block, want 3", got)
+	}
+}
+
+func TestCloseCancelsReadAhead(t *testing.T) {
+	data := stripedRows(8)
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	l.At(0, 0)
+
+	// The first row is cached; the read-ahead of the next ones stalls.
+	r := &stallingReader{Reader: bytes.NewReader(data), started: make(chan struct{})}
+	l.reader = r
+	l.state.readAhead = 2
+	l.At(0, 0)
+	<-r.started
+	closed := make(chan error)
+	go func() { closed <- l.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close() = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not cancel the read-ahead in progress")
+	}
+}
@@ -0,0 +1,207 @@
+// Package impl contains internal TIFF image decoding implementations.
+// This file implements parallel reads of rectangular regions.
+package impl
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+)
+
+// ReadRegion copies the samples of the given bands for every pixel in r into
+// dst, like ReadBands, decoding the strips or tiles intersecting r with up to
+// concurrency goroutines. A concurrency <= 0 uses runtime.GOMAXPROCS(0).
+//
+// r is read in batches of blocks that fit the cache budget. The uncached
+// blocks of a batch lying close together in the file are first fetched with
+// single reads, as configured by LoadOptions.CoalesceGap and
+// LoadOptions.CoalesceMaxBytes. Each goroutine then decodes whole blocks and
+// copies their part of r into dst, so blocks are decompressed in parallel.
+// If ctx is cancelled, ReadRegion stops after the blocks in progress and
+// returns ctx.Err(); dst is then partially filled.
+func (l *lazyImage) ReadRegion(ctx context.Context, r image.Rectangle, bands []int, dst []byte, concurrency int) error {
+	bands, err := l.checkRegion(r, bands, dst)
+	if err != nil {
+		return err
+	}
+	if r.Empty() {
+		return nil
+	}
+	end, err := l.state.begin()
+	if err != nil {
+		return err
+	}
+	defer end()
+
+	units := l.blockRegions(r)
+	for len(units) > 0 {
+		batch := units[:l.batchLen(units)]
+		units = units[len(batch):]
+		if err := l.prefetch(ctx, batch, concurrency); err != nil {
+			return err
+		}
+		err := parallel(ctx, len(batch), concurrency, func(ctx context.Context, i int) error {
+			return l.copyRegion(ctx, batch[i], r, bands, dst)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchLen returns the number of leading units whose decoded blocks fit the
+// cache budget together, at least one. Fetching more at once would evict
+// blocks of the batch before their pixels are copied.
+func (l *lazyImage) batchLen(units []image.Rectangle) int {
+	bw, bh := l.blockSize()
+	unitBytes := int64(l.format.rowBytes(bw) * bh)
+	n := int(l.cache.Budget() / max(unitBytes, 1))
+	return min(max(n, 1), len(units))
+}
+
+// parallel calls fn for 0 <= i < n on up to concurrency goroutines, or
+// runtime.GOMAXPROCS(0) if concurrency <= 0. It stops handing out work after
+// the first error or when ctx is cancelled and returns that error or
+// ctx.Err().
+func parallel(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	if n == 0 {
+		return ctx.Err()
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	concurrency = min(concurrency, n)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	errs := make(chan error, concurrency)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(ctx, i); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+	}
+	// Only a cancellation by the caller is left; ours happen after an error.
+	return ctx.Err()
+}
+
+// copyRegion copies the pixels of unit, a part of the region r, into dst,
+// which holds the samples of r in row-major order.
+func (l *lazyImage) copyRegion(ctx context.Context, unit, r image.Rectangle, bands []int, dst []byte) error {
+	for y := unit.Min.Y; y < unit.Max.Y; y++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		i := ((y-r.Min.Y)*r.Dx() + unit.Min.X - r.Min.X) * len(bands)
+		for x := unit.Min.X; x < unit.Max.X; x++ {
+			px, err := l.pixel(ctx, x, y)
+			if err != nil {
+				return err
+			}
+			for _, b := range bands {
+				dst[i] = px[b]
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+// checkRegion validates the arguments of ReadBands and ReadRegion and that
+// the image is open, and returns the selected bands, expanding an empty selection to all bands.
+func (l *lazyImage) checkRegion(r image.Rectangle, bands []int, dst []byte) ([]int, error) {
+	if err := l.checkOpen(); err != nil {
+		return nil, err
+	}
+	if !r.In(l.Bounds()) {
+		return nil, fmt.Errorf("rectangle %v outside image bounds %v", r, l.Bounds())
+	}
+	if len(bands) == 0 {
+		bands = make([]int, l.format.samples)
+		for i := range bands {
+			bands[i] = i
+		}
+	}
+	for _, b := range bands {
+		if b < 0 || b >= l.format.samples {
+			return nil, fmt.Errorf("band %d out of range [0, %d)", b, l.format.samples)
+		}
+	}
+	if need := r.Dx() * r.Dy() * len(bands); len(dst) < need {
+		return nil, fmt.Errorf("destination too small: %d bytes, need %d", len(dst), need)
+	}
+	return bands, nil
+}
+
+// blockRegions splits r, given in display coordinates, into the parts
+// covered by individual stored blocks (tiles, or strips for striped images).
+func (l *lazyImage) blockRegions(r image.Rectangle) []image.Rectangle {
+	w, h := l.header.Width, l.header.Height
+	o := l.orientation
+	stored := l.storedRect(r)
+
+	bw, bh := l.blockSize()
+	var units []image.Rectangle
+	for by := stored.Min.Y / bh; by*bh < stored.Max.Y; by++ {
+		for bx := stored.Min.X / bw; bx*bw < stored.Max.X; bx++ {
+			block := image.Rect(bx*bw, by*bh, (bx+1)*bw, (by+1)*bh).Intersect(stored)
+			display := mapRect(block, func(x, y int) (int, int) { return o.FromStored(x, y, w, h) })
+			units = append(units, display)
+		}
+	}
+	return units
+}
+
+// storedRect maps r from display coordinates to the stored layout.
+func (l *lazyImage) storedRect(r image.Rectangle) image.Rectangle {
+	w, h := l.header.Width, l.header.Height
+	o := l.orientation
+	return mapRect(r, func(x, y int) (int, int) { return o.ToStored(x, y, w, h) })
+}
+
+// blockSize returns the size of the stored blocks: tiles, or whole-width
+// strips of RowsPerStrip rows.
+func (l *lazyImage) blockSize() (int, int) {
+	if len(l.header.TileOffsets) > 0 {
+		return l.header.TileWidth, l.header.TileHeight
+	}
+	return max(l.header.Width, 1), max(l.header.RowsPerStrip, 1)
+}
+
+// mapRect returns the rectangle covering the pixels of r mapped by f, which
+// must map axis-aligned rectangles onto axis-aligned rectangles.
+func mapRect(r image.Rectangle, f func(x, y int) (int, int)) image.Rectangle {
+	x0, y0 := f(r.Min.X, r.Min.Y)
+	x1, y1 := f(r.Max.X-1, r.Max.Y-1)
+	return image.Rect(min(x0, x1), min(y0, y1), max(x0, x1)+1, max(y0, y1)+1)
+}
@@ -0,0 +1,247 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/orientation"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// countingReader counts the reads and bytes read from a bytes.Reader.
+type countingReader struct {
+	*bytes.Reader
+	reads, bytes atomic.Int64
+}
+
+func (r *countingReader) ReadAt(p []byte, off int64) (int, error) {
+	r.reads.Add(1)
+	r.bytes.Add(int64(len(p)))
+	return r.Reader.ReadAt(p, off)
+}
+
+// tiledGray returns a deflated grayscale image of w × h pixels in tiles of
+// 16 × 16 pixels together with its pixels.
+func tiledGray(w, h int) (data, pix []byte) {
+	pix = make([]byte, w*h)
+	for i := range pix {
+		pix[i] = byte(i*7 + i/w)
+	}
+	var tiles [][]byte
+	for _, tile := range tifftest.Tiles(pix, w, h, 1, 16, 16) {
+		tiles = append(tiles, tifftest.Deflate(tile))
+	}
+	data = tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(w, h),
+			tifftest.Short(tifftag.Compression, 8),
+			tifftest.Short(tifftag.TileWidth, 16),
+			tifftest.Short(tifftag.TileLength, 16)),
+		Blocks: tiles,
+		Tiled:  true,
+	})
+	return data, pix
+}
+
+func TestReadRegion(t *testing.T) {
+	const w, h = 40, 24
+	data, pix := tiledGray(w, h)
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, w, h),
+		image.Rect(5, 3, 33, 20),
+		image.Rect(16, 16, 17, 17),
+	} {
+		dst := make([]byte, r.Dx()*r.Dy())
+		if err := l.ReadRegion(context.Background(), r, nil, dst, 3); err != nil {
+			t.Fatalf("ReadRegion(%v) = %v", r, err)
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if got, want := dst[(y-r.Min.Y)*r.Dx()+x-r.Min.X], pix[y*w+x]; got != want {
+					t.Fatalf("ReadRegion(%v) at (%d, %d) = %d, want %d", r, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestReadRegionOrientation(t *testing.T) {
+	data, _ := tiledGray(40, 24)
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for o := orientation.TopLeft; o <= orientation.LeftBottom; o++ {
+		l := Orient(img, o).(*lazyImage)
+		r := image.Rect(3, 5, 20, 17)
+		dst := make([]byte, r.Dx()*r.Dy())
+		if err := l.ReadRegion(context.Background(), r, nil, dst, 2); err != nil {
+			t.Fatalf("orientation %d: ReadRegion() = %v", o, err)
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				want := l.At(x, y).(color.RGBA).R
+				if got := dst[(y-r.Min.Y)*r.Dx()+x-r.Min.X]; got != want {
+					t.Fatalf("orientation %d: ReadRegion at (%d, %d) = %d, want %d", o, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestReadRegionBands(t *testing.T) {
+	l := multiband(t, 3, 2)
+	for _, bands := range [][]int{nil, {4, 0}, {1}, {2, 2, 3}} {
+		n := len(bands)
+		if n == 0 {
+			n = 5
+		}
+		want := make([]byte, 3*2*n)
+		if err := l.ReadBands(l.Bounds(), bands, want); err != nil {
+			t.Fatal(err)
+		}
+		dst := make([]byte, len(want))
+		if err := l.ReadRegion(context.Background(), l.Bounds(), bands, dst, 1); err != nil {
+			t.Fatalf("ReadRegion(bands %v) = %v", bands, err)
+		}
+		if !bytes.Equal(dst, want) {
+			t.Errorf("ReadRegion(bands %v) = %v, want %v", bands, dst, want)
+		}
+	}
+}
+
+func TestReadRegionDefaultConcurrency(t *testing.T) {
+	data, pix := tiledGray(40, 24)
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, concurrency := range []int{0, -1} {
+		dst := make([]byte, len(pix))
+		if err := img.(*lazyImage).ReadRegion(context.Background(), img.Bounds(), nil, dst, concurrency); err != nil {
+			t.Fatalf("ReadRegion(concurrency %d) = %v", concurrency, err)
+		}
+		if !bytes.Equal(dst, pix) {
+			t.Errorf("ReadRegion(concurrency %d) returned wrong pixels", concurrency)
+		}
+	}
+}
+
+func TestReadRegionErrors(t *testing.T) {
+	data, _ := tiledGray(16, 16)
+	img, err := Load(context.Background(), bytes.NewReader(data), LoadOptions{CacheBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := img.(*lazyImage)
+	tests := []struct {
+		name  string
+		r     image.Rectangle
+		bands []int
+		dst   int
+	}{
+		{"outside bounds", image.Rect(8, 8, 17, 16), nil, 1 << 10},
+		{"band out of range", image.Rect(0, 0, 2, 2), []int{1}, 1 << 10},
+		{"destination too small", image.Rect(0, 0, 4, 4), nil, 15},
+		{"destination too small for bands", image.Rect(0, 0, 4, 4), []int{0, 0}, 31},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := l.ReadRegion(context.Background(), tt.r, tt.bands, make([]byte, tt.dst), 1); err == nil {
+				t.Errorf("ReadRegion() succeeded, want error")
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.ReadRegion(ctx, l.Bounds(), nil, make([]byte, 256), 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadRegion(cancelled) = %v, want context.Canceled", err)
+	}
+}
+
+func TestReadRegionWithinCacheBudget(t *testing.T) {
+	const w, h = 256, 256
+	data, pix := tiledGray(w, h)
+	r := &countingReader{Reader: bytes.NewReader(data)}
+	// Room for 4 of the 256 tiles.
+	img, err := Load(context.Background(), r, LoadOptions{CacheBytes: 4 * 16 * 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.bytes.Store(0)
+
+	dst := make([]byte, w*h)
+	if err := img.(*lazyImage).ReadRegion(context.Background(), img.Bounds(), nil, dst, 4); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dst, pix) {
+		t.Fatalf("ReadRegion returned wrong pixels")
+	}
+	if n := r.bytes.Load(); n > int64(len(data)) {
+		t.Errorf("read %d bytes of a %d byte file, want every tile read once", n, len(data))
+	}
+}
+
+func TestReadRegionCoalesces(t *testing.T) {
+	data, _ := tiledGray(64, 64)
+	for _, tt := range []struct {
+		name      string
+		gap       int64
+		wantReads int64
+	}{
+		{"coalesced", 0, 1},
+		{"separate", -1, 16},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &countingReader{Reader: bytes.NewReader(data)}
+			img, err := Load(context.Background(), r, LoadOptions{CacheBytes: 1 << 20, CoalesceGap: tt.gap})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.reads.Store(0)
+			if err := img.(*lazyImage).ReadRegion(context.Background(), img.Bounds(), nil, make([]byte, 64*64), 4); err != nil {
+				t.Fatal(err)
+			}
+			if n := r.reads.Load(); n != tt.wantReads {
+				t.Errorf("%d reads, want %d", n, tt.wantReads)
+			}
+		})
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	err := parallel(context.Background(), 100, 4, func(ctx context.Context, i int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[i] = true
+		return nil
+	})
+	if err != nil || len(seen) != 100 {
+		t.Errorf("parallel() = %v with %d calls, want nil with 100", err, len(seen))
+	}
+
+	errStop := errors.New("stop")
+	var calls atomic.Int32
+	err = parallel(context.Background(), 100, 1, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		return errStop
+	})
+	if err != errStop || calls.Load() != 1 {
+		t.Errorf("parallel() = %v after %d calls, want %v after 1", err, calls.Load(), errStop)
+	}
+}
@@ -3,27 +3,25 @@
 package impl
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"image"
-	"image/color"
 	"io"
-	"sync"
-
-	lru "github.com/hashicorp/golang-lru"
 
+	"github.com/echoflaresat/tiff/blockcache"
 	"github.com/echoflaresat/tiff/compression"
-	"github.com/echoflaresat/tiff/photometric"
 )
 
 // stripedTiff represents a memory-efficient view of a TIFF image using strips.
 //
 // This implementation accesses pixel data lazily by reading only the necessary
 // strip from the underlying io.ReaderAt when At(x, y) is called.
+//
+// Rows are read without locking, so the reader must support parallel ReadAt
+// calls as required by io.ReaderAt.
 type stripedTiff struct {
-	header TiffHeader
-	reader io.ReaderAt
-	cache  *lru.Cache // maps tileIndex -> []byte
-	mutex  *sync.Mutex
+	*lazyImage
 }
 
 // LoadStripedTiff attempts to parse and load a TIFF image using a striped layout.
@@ -33,110 +31,133 @@ type stripedTiff struct {
 //   - Compression: None
 //   - PhotometricInterpretation: RGB or BlackIsZero
 //   - BitsPerSample: 8-bit per channel
+//   - ExtraSamples: associated or unassociated alpha; unspecified samples are skipped
+//
+// Any number of samples per pixel is accepted; the individual bands are
+// available through the band accessors of the returned image.
+//
+// If the file contains a transparency mask for the image (NewSubfileType
+// mask bit), it is applied to the alpha channel.
 //
 // Note: The returned image.Image requires that the `reader` remains open for future reads.
 func LoadStripedTiff(reader io.ReaderAt) (image.Image, error) {
-	header, err := parseTiffHeader(reader)
+	chain, err := newHeaderChain(reader)
+	if err != nil {
+		return nil, err
+	}
+	chain.readMasks(0)
+
+	cache := blockcache.Default.Handle(blockcache.NewFileID(), 0)
+	t, err := newStripedTiff(reader, chain.headers[0], cache)
 	if err != nil {
 		return nil, err
 	}
+	t.attachMask(reader, chain.headers, 0, blockcache.Default)
+	return t, nil
+}
 
+// newStripedTiff validates a single striped directory and returns its lazy image.
+// Decoded rows are cached in cache, keyed by strip and row.
+func newStripedTiff(reader io.ReaderAt, header TiffHeader, cache *blockcache.Handle) (*stripedTiff, error) {
 	if header.Compression != compression.None {
 		return nil, fmt.Errorf("unsupported compression: %d", header.Compression)
 	}
-	if header.Photometric != photometric.RGB && header.Photometric != photometric.BlackIsZero {
-		return nil, fmt.Errorf("expected RGB or grayscale photometric interpretation, got %d", header.Photometric)
-	}
-
-	switch header.Photometric {
-	case photometric.BlackIsZero:
-		if header.SamplesPerPixel != 1 || header.BitsPerSample[0] != 8 {
-			return nil, fmt.Errorf("unsupported grayscale format")
-		}
-	case photometric.RGB:
-		if header.SamplesPerPixel != 3 || header.BitsPerSample[0] != 8 {
-			return nil, fmt.Errorf("unsupported RGB format")
-		}
-	default:
-		return nil, fmt.Errorf("unsupported photometric: %d", header.Photometric)
+	format, err := newPixelFormat(header)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(header.StripOffsets) == 0 || len(header.StripOffsets) != len(header.StripByteCounts) {
 		return nil, fmt.Errorf("invalid strip offset/length")
 	}
-
-	cache, err := lru.New(256)
-	if err != nil {
-		return nil, fmt.Errorf("could not create cache; %w", err)
+	if err := checkLayout(header, format); err != nil {
+		return nil, err
+	}
+	if strips := ceilDiv(header.Height, max(header.RowsPerStrip, 1)); len(header.StripOffsets) < strips {
+		return nil, fmt.Errorf("invalid strip offset/length: %d strips, want %d", len(header.StripOffsets), strips)
 	}
 
-	return &stripedTiff{
-		header: header,
+	t := &stripedTiff{}
+	t.lazyImage = &lazyImage{
 		reader: reader,
+		size:   readerSize(reader),
+		header: header,
+		format: format,
 		cache:  cache,
-		mutex:  &sync.Mutex{},
-	}, nil
-}
-
-// ColorModel returns the color model used by the TIFF image.
-// Always returns color.RGBAModel for simplicity.
-func (t *stripedTiff) ColorModel() color.Model {
-	return color.RGBAModel
-}
-
-// Bounds returns the image rectangle.
-func (t *stripedTiff) Bounds() image.Rectangle {
-	return image.Rect(0, 0, t.header.Width, t.header.Height)
+		state:  newImageState(),
+		pixel:  t.pixel,
+		blocks: t.blocks,
+	}
+	return t, nil
 }
 
-// At returns the color of the pixel at (x, y).
+// pixel returns the raw samples of the pixel at (x, y).
 // This function reads the relevant bytes from the correct strip using t.reader.
-func (t *stripedTiff) At(x, y int) color.Color {
+func (t *stripedTiff) pixel(ctx context.Context, x, y int) ([]byte, error) {
+	if err := t.checkOpen(); err != nil {
+		return nil, err
+	}
 	h := t.header
 
 	strip := y / h.RowsPerStrip
 	localY := y % h.RowsPerStrip
-	bytesPerPixel := h.SamplesPerPixel
-	row := t.getRow(strip, localY, bytesPerPixel)
-
-	base := x * bytesPerPixel
-
-	switch h.Photometric {
-	case photometric.RGB:
-		return color.RGBA{R: row[base+0], G: row[base+1], B: row[base+2], A: 255}
-	case photometric.BlackIsZero:
-		v := row[base]
-		return color.RGBA{R: v, G: v, B: v, A: 255}
-	default:
-		panic(fmt.Sprintf("unsupported PhotometricInterpretation: %d", h.Photometric))
+	t.advance(strip)
+	row, err := t.getRow(ctx, strip, localY)
+	if err != nil {
+		return nil, err
 	}
+	return t.format.sample(row, x), nil
 }
 
 // getRow returns a full row of raw bytes for (strip, rowInStrip).
-// Fast path: no lock on reader; RLock+Get on cache.
-// On miss: Lock, double-check, then single-threaded ReadAt and cache.
-func (t *stripedTiff) getRow(strip, rowInStrip, bpp int) []byte {
-	key := (uint64(strip) << 32) | uint64(uint32(rowInStrip))
-
-	// Try cache under read lock.
-	if row, ok := t.cache.Get(key); ok {
-		return row.([]byte)
+// Rows of different strips or rows are read concurrently; concurrent misses
+// on the same row share a single read. Rows of memory-backed readers are
+// sliced from memory and bypass the cache.
+func (t *stripedTiff) getRow(ctx context.Context, strip, rowInStrip int) ([]byte, error) {
+	rowSize := t.format.rowBytes(t.header.Width)
+	offset := int64(t.header.StripOffsets[strip]) + int64(rowInStrip)*int64(rowSize)
+	if row, ok := mapped(t.reader, offset, int64(rowSize)); ok {
+		return row, nil
 	}
+	return t.cache.LoadContext(ctx, rowKey(strip, rowInStrip), func(ctx context.Context) ([]byte, error) {
+		if err := t.checkBlock(offset, int64(rowSize)); err != nil {
+			return nil, fmt.Errorf("could not read row strip=%d row=%d: %w", strip, rowInStrip, err)
+		}
+		row := make([]byte, rowSize)
+		n, err := readAt(ctx, t.reader, row, offset)
+		if n != len(row) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("could not read row strip=%d row=%d: read %d/%d bytes: %w",
+				strip, rowInStrip, n, len(row), err)
+		}
+		return row, nil
+	})
+}
 
+// blocks returns the cached rows intersecting r, given in stored coordinates.
+// Rows are cached individually, so each row is a block.
+func (t *stripedTiff) blocks(r image.Rectangle) []blockRange {
 	h := t.header
-	rowSize := h.Width * bpp
-	offset := int64(h.StripOffsets[strip] + (rowInStrip*h.Width)*bpp)
-
-	row := make([]byte, rowSize)
-	t.mutex.Lock()
-	n, err := t.reader.ReadAt(row, offset)
-	defer t.mutex.Unlock()
-
-	if err != nil || n != len(row) {
-		panic(fmt.Sprintf("could not read row strip=%d row=%d: read %d/%d bytes, err=%v",
-			strip, rowInStrip, n, len(row), err))
+	rowSize := int64(t.format.rowBytes(h.Width))
+	var blocks []blockRange
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		strip, row := y/h.RowsPerStrip, y%h.RowsPerStrip
+		if strip >= len(h.StripOffsets) {
+			break
+		}
+		blocks = append(blocks, blockRange{
+			key:    rowKey(strip, row),
+			offset: int64(h.StripOffsets[strip]) + int64(row)*rowSize,
+			length: rowSize,
+			decode: func(raw []byte) ([]byte, error) { return bytes.Clone(raw), nil },
+		})
 	}
+	return blocks
+}
 
-	t.cache.Add(key, row)
-	return row
+// rowKey returns the cache key of a row within a strip.
+func rowKey(strip, rowInStrip int) uint64 {
+	return (uint64(strip) << 32) | uint64(uint32(rowInStrip))
 }
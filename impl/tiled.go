@@ -5,28 +5,26 @@ package impl
 import (
 	"bytes"
 	"compress/zlib"
+	"context"
 	"fmt"
 	"image"
-	"image/color"
 	"io"
-	"math"
-	"sync"
 
+	"github.com/echoflaresat/tiff/blockcache"
 	"github.com/echoflaresat/tiff/compression"
-	"github.com/echoflaresat/tiff/photometric"
-	lru "github.com/hashicorp/golang-lru"
 )
 
 // tiledTiff provides an image.Image implementation for tiled TIFF images.
 //
-// It supports lazy tile loading and decompression (Deflate), using an LRU cache
-// to avoid redundant I/O. Pixel values are accessed using the At(x, y) method,
+// It supports lazy tile loading and decompression (Deflate), using the shared
+// block cache to avoid redundant I/O. Pixel values are accessed using the At(x, y) method,
 // which transparently reads and decompresses the necessary tile on demand.
+//
+// Independent tiles are read and decompressed concurrently, so the reader
+// must support parallel ReadAt calls as required by io.ReaderAt; concurrent
+// requests for the same tile are served by a single load.
 type tiledTiff struct {
-	header TiffHeader
-	reader io.ReaderAt
-	cache  *lru.Cache // maps tileIndex -> []byte
-	mutex  *sync.Mutex
+	*lazyImage
 }
 
 // LoadTiledTiff attempts to parse a tiled TIFF image from an io.ReaderAt,
@@ -36,135 +34,182 @@ type tiledTiff struct {
 //   - Compression: None, Deflate (zlib)
 //   - PhotometricInterpretation: RGB or BlackIsZero
 //   - BitsPerSample: 8-bit
+//   - ExtraSamples: associated or unassociated alpha; unspecified samples are skipped
+//
+// Any number of samples per pixel is accepted; the individual bands are
+// available through the band accessors of the returned image.
+//
+// If the file contains a transparency mask for the image (NewSubfileType
+// mask bit), it is applied to the alpha channel.
 //
 // The returned image.Image requires the caller to keep the reader open
 // for the lifetime of the image. This decoder avoids loading the full
 // image into memory.
 func LoadTiledTiff(reader io.ReaderAt) (image.Image, error) {
-	header, err := parseTiffHeader(reader)
+	chain, err := newHeaderChain(reader)
 	if err != nil {
 		return nil, err
 	}
+	chain.readMasks(0)
 
+	cache := blockcache.Default.Handle(blockcache.NewFileID(), 0)
+	t, err := newTiledTiff(reader, chain.headers[0], cache)
+	if err != nil {
+		return nil, err
+	}
+	t.attachMask(reader, chain.headers, 0, blockcache.Default)
+	return t, nil
+}
+
+// newTiledTiff validates a single tiled directory and returns its lazy image.
+// Decoded tiles are cached in cache, keyed by tile index.
+func newTiledTiff(reader io.ReaderAt, header TiffHeader, cache *blockcache.Handle) (*tiledTiff, error) {
 	if header.Compression != compression.None && header.Compression != compression.Deflate {
 		return nil, fmt.Errorf("unsupported compression: %d", header.Compression)
 	}
-	if header.Photometric != photometric.RGB && header.Photometric != photometric.BlackIsZero {
-		return nil, fmt.Errorf("unsupported photometric interpretation: %d", header.Photometric)
-	}
-	switch header.Photometric {
-	case photometric.BlackIsZero:
-		if header.SamplesPerPixel != 1 || header.BitsPerSample[0] != 8 {
-			return nil, fmt.Errorf("unsupported grayscale format")
-		}
-	case photometric.RGB:
-		if header.SamplesPerPixel != 3 || header.BitsPerSample[0] != 8 {
-			return nil, fmt.Errorf("unsupported RGB format")
-		}
-	default:
-		return nil, fmt.Errorf("unsupported photometric: %d", header.Photometric)
+	format, err := newPixelFormat(header)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(header.TileOffsets) == 0 || len(header.TileOffsets) != len(header.TileByteCounts) {
 		return nil, fmt.Errorf("invalid tile offset/length")
 	}
-
-	cache, err := lru.New(header.Width / header.TileWidth)
-	if err != nil {
-		return nil, fmt.Errorf("could not create cache; %w", err)
+	if err := checkLayout(header, format); err != nil {
+		return nil, err
+	}
+	tiles := ceilDiv(header.Width, header.TileWidth) * ceilDiv(header.Height, header.TileHeight)
+	if len(header.TileOffsets) < tiles {
+		return nil, fmt.Errorf("invalid tile offset/length: %d tiles, want %d", len(header.TileOffsets), tiles)
 	}
 
-	return &tiledTiff{
-		header: header,
+	t := &tiledTiff{}
+	t.lazyImage = &lazyImage{
 		reader: reader,
+		size:   readerSize(reader),
+		header: header,
+		format: format,
 		cache:  cache,
-		mutex:  &sync.Mutex{},
-	}, nil
-}
-
-// ColorModel returns the color model of the image.
-// Always returns color.RGBAModel.
-func (t *tiledTiff) ColorModel() color.Model {
-	return color.RGBAModel
-}
-
-// Bounds returns the rectangular bounds of the image.
-func (t *tiledTiff) Bounds() image.Rectangle {
-	return image.Rect(0, 0, t.header.Width, t.header.Height)
+		state:  newImageState(),
+		pixel:  t.pixel,
+		blocks: t.blocks,
+	}
+	return t, nil
 }
 
-// At returns the color of the pixel at (x, y).
+// pixel returns the raw samples of the pixel at (x, y).
 // The underlying tile is loaded and decompressed on demand if needed.
-func (t *tiledTiff) At(x, y int) color.Color {
+func (t *tiledTiff) pixel(ctx context.Context, x, y int) ([]byte, error) {
+	if err := t.checkOpen(); err != nil {
+		return nil, err
+	}
 	h := t.header
 
 	tileX := x / h.TileWidth
 	tileY := y / h.TileHeight
-	tilesAcross := int(math.Ceil(float64(h.Width) / float64(h.TileWidth)))
-	tileIndex := tileY*tilesAcross + tileX
+	tileIndex := tileY*t.tilesAcross() + tileX
+	t.advance(tileIndex)
 
-	var tile []byte
-	if val, ok := t.cache.Get(tileIndex); ok {
-		tile = val.([]byte)
-	} else {
-		tile = t.loadTile(tileIndex)
-		t.cache.Add(tileIndex, tile)
+	tile, err := t.tile(ctx, tileIndex)
+	if err != nil {
+		return nil, err
 	}
 
 	localX := x % h.TileWidth
 	localY := y % h.TileHeight
-	rowStride := h.TileWidth * h.SamplesPerPixel
-	pixOffset := localY*rowStride + localX*h.SamplesPerPixel
-
-	switch h.Photometric {
-	case photometric.RGB:
-		return color.RGBA{
-			R: tile[pixOffset],
-			G: tile[pixOffset+1],
-			B: tile[pixOffset+2],
-			A: 255,
-		}
+	rowStride := t.format.rowBytes(h.TileWidth)
+	rowOffset := localY * rowStride
 
-	case photometric.BlackIsZero:
-		return color.RGBA{
-			R: tile[pixOffset],
-			G: tile[pixOffset],
-			B: tile[pixOffset],
-			A: 255,
-		}
+	if rowOffset+rowStride > len(tile) {
+		return nil, fmt.Errorf("tile %d is truncated: %d bytes", tileIndex, len(tile))
+	}
+	return t.format.sample(tile[rowOffset:rowOffset+rowStride], localX), nil
+}
 
-	default:
-		panic(fmt.Sprintf("unsupported PhotometricInterpretation: %d", h.Photometric))
+// tile returns the decoded tile at the given index. Uncompressed tiles of
+// memory-backed readers are sliced from memory and bypass the cache.
+func (t *tiledTiff) tile(ctx context.Context, index int) ([]byte, error) {
+	h := t.header
+	if h.Compression == compression.None {
+		if tile, ok := mapped(t.reader, int64(h.TileOffsets[index]), int64(h.TileByteCounts[index])); ok {
+			return tile, nil
+		}
 	}
+	return t.cache.LoadContext(ctx, uint64(index), func(ctx context.Context) ([]byte, error) {
+		return t.loadTile(ctx, index)
+	})
 }
 
 // loadTile loads and optionally decompresses a single tile at the given index.
-// It panics on I/O or decompression errors.
-func (t *tiledTiff) loadTile(index int) []byte {
-
+func (t *tiledTiff) loadTile(ctx context.Context, index int) ([]byte, error) {
 	h := t.header
-	offset := h.TileOffsets[index]
-	byteCount := h.TileByteCounts[index]
+	offset := int64(h.TileOffsets[index])
+	byteCount := int64(h.TileByteCounts[index])
+	if err := t.checkBlock(offset, byteCount); err != nil {
+		return nil, fmt.Errorf("failed to read tile %d: %w", index, err)
+	}
 
 	buf := make([]byte, byteCount)
-	t.mutex.Lock()
-	_, err := t.reader.ReadAt(buf, int64(offset))
-	t.mutex.Unlock()
-	if err != nil {
-		panic(fmt.Sprintf("failed to read tile %d: %v", index, err))
+	if n, err := readAt(ctx, t.reader, buf, offset); n != len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("failed to read tile %d: %w", index, err)
 	}
 
-	if h.Compression == compression.Deflate {
-		r, err := zlib.NewReader(io.NopCloser(bytes.NewReader(buf)))
+	if h.Compression == compression.None {
+		return buf, nil
+	}
+	return t.decodeTile(buf)
+}
+
+// decodeTile decompresses the stored bytes of a tile. The result does not
+// share memory with raw. Data beyond the size of a decoded tile is ignored.
+func (t *tiledTiff) decodeTile(raw []byte) ([]byte, error) {
+	if t.header.Compression == compression.Deflate {
+		r, err := zlib.NewReader(io.NopCloser(bytes.NewReader(raw)))
 		if err != nil {
-			panic(fmt.Sprintf("zlib decompression error: %v", err))
+			return nil, fmt.Errorf("zlib decompression error: %w", err)
 		}
 		defer r.Close()
-		tile, err := io.ReadAll(r)
+		size := int64(t.format.rowBytes(t.header.TileWidth)) * int64(t.header.TileHeight)
+		tile, err := io.ReadAll(io.LimitReader(r, size))
 		if err != nil {
-			panic(fmt.Sprintf("zlib read error: %v", err))
+			return nil, fmt.Errorf("zlib read error: %w", err)
+		}
+		return tile, nil
+	}
+	return bytes.Clone(raw), nil
+}
+
+// blocks returns the tiles intersecting r, given in stored coordinates.
+func (t *tiledTiff) blocks(r image.Rectangle) []blockRange {
+	h := t.header
+	across := t.tilesAcross()
+	var blocks []blockRange
+	for ty := r.Min.Y / h.TileHeight; ty*h.TileHeight < r.Max.Y; ty++ {
+		for tx := r.Min.X / h.TileWidth; tx*h.TileWidth < r.Max.X; tx++ {
+			index := ty*across + tx
+			if index >= len(h.TileOffsets) {
+				continue
+			}
+			blocks = append(blocks, blockRange{
+				key:    uint64(index),
+				offset: int64(h.TileOffsets[index]),
+				length: int64(h.TileByteCounts[index]),
+				decode: t.decodeTile,
+			})
 		}
-		return tile
 	}
-	return buf
+	return blocks
+}
+
+// tilesAcross returns the number of tiles in a row of tiles.
+func (t *tiledTiff) tilesAcross() int {
+	return ceilDiv(t.header.Width, t.header.TileWidth)
+}
+
+// ceilDiv returns a / b rounded up.
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
 }
@@ -0,0 +1,50 @@
+// Package register registers the TIFF format with the image package.
+//
+// It is kept free of dependencies beyond the standard library so that it is
+// initialized before golang.org/x/image/tiff: packages ready for
+// initialization are initialized in import path order, and
+// "github.com/echoflaresat/..." sorts before "golang.org/...". image.Decode
+// uses the first registered format whose magic string matches, so this
+// package takes precedence. The actual decoders are installed by package
+// tiff during its own initialization.
+package register
+
+import (
+	"errors"
+	"image"
+	"io"
+)
+
+// Magic strings of little- and big-endian classic TIFF and BigTIFF files.
+var magics = []string{"II*\x00", "MM\x00*", "II+\x00", "MM\x00+"}
+
+// Decode and DecodeConfig are set by package tiff.
+var (
+	Decode       func(r io.Reader) (image.Image, error)
+	DecodeConfig func(r io.Reader) (image.Config, error)
+)
+
+// errNotInstalled is returned if the decoders were not installed.
+var errNotInstalled = errors.New("tiff: decoder not installed")
+
+func init() {
+	for _, magic := range magics {
+		image.RegisterFormat("tiff", magic, decode, decodeConfig)
+	}
+}
+
+// decode calls Decode, which is only known after package tiff is initialized.
+func decode(r io.Reader) (image.Image, error) {
+	if Decode == nil {
+		return nil, errNotInstalled
+	}
+	return Decode(r)
+}
+
+// decodeConfig calls DecodeConfig, which is only known after package tiff is initialized.
+func decodeConfig(r io.Reader) (image.Config, error) {
+	if DecodeConfig == nil {
+		return image.Config{}, errNotInstalled
+	}
+	return DecodeConfig(r)
+}
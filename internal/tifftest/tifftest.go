@@ -0,0 +1,321 @@
+// Package tifftest builds small TIFF and BigTIFF files in memory for tests.
+//
+//	data := tifftest.Build(tifftest.IFD{
+//		Entries: tifftest.Gray(4, 4),
+//		Blocks:  [][]byte{pixels},
+//	})
+//
+// Entries are written sorted by tag and strip or tile offsets and byte
+// counts are filled in from Blocks, so tests only describe what they check.
+package tifftest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// Entry is a directory entry to write.
+type Entry struct {
+	Tag  tifftag.Tag
+	Type fieldtype.Type
+
+	// Values holds the values of integer and floating point types (as IEEE
+	// bits), and numerator/denominator pairs of rational types.
+	Values []uint64
+
+	// Raw holds the bytes of BYTE, ASCII and UNDEFINED entries, and of
+	// other types if Values is nil. Its length determines the count.
+	Raw []byte
+}
+
+// IFD is a directory to write together with its pixel data.
+type IFD struct {
+	Entries []Entry
+
+	// Blocks are written before the directory and referenced by
+	// StripOffsets/StripByteCounts, or TileOffsets/TileByteCounts if Tiled.
+	Blocks [][]byte
+	Tiled  bool
+
+	// SubIFDs are written before the directory and referenced by a LONG (or
+	// LONG8) entry for each tag, e.g. tifftag.ExifIFD.
+	SubIFDs map[tifftag.Tag]IFD
+
+	// Next overrides the offset of the next directory if not zero, e.g. to
+	// produce a corrupt chain.
+	Next uint64
+}
+
+// File describes a whole file.
+type File struct {
+	// ByteOrder defaults to little-endian.
+	ByteOrder binary.ByteOrder
+	BigTIFF   bool
+	IFDs      []IFD
+}
+
+// Build returns a little-endian classic TIFF file with the given chain of
+// directories.
+func Build(ifds ...IFD) []byte {
+	return File{IFDs: ifds}.Bytes()
+}
+
+// Short returns a SHORT entry.
+func Short(tag tifftag.Tag, v ...uint64) Entry {
+	return Entry{Tag: tag, Type: fieldtype.Short, Values: v}
+}
+
+// Long returns a LONG entry.
+func Long(tag tifftag.Tag, v ...uint64) Entry {
+	return Entry{Tag: tag, Type: fieldtype.Long, Values: v}
+}
+
+// ASCII returns a NUL-terminated ASCII entry.
+func ASCII(tag tifftag.Tag, s string) Entry {
+	return Entry{Tag: tag, Type: fieldtype.ASCII, Raw: append([]byte(s), 0)}
+}
+
+// Double returns a DOUBLE entry.
+func Double(tag tifftag.Tag, v ...float64) Entry {
+	e := Entry{Tag: tag, Type: fieldtype.Double}
+	for _, f := range v {
+		e.Values = append(e.Values, math.Float64bits(f))
+	}
+	return e
+}
+
+// Rational returns a RATIONAL entry with a single value.
+func Rational(tag tifftag.Tag, num, den uint32) Entry {
+	return Entry{Tag: tag, Type: fieldtype.Rational, Values: []uint64{uint64(num), uint64(den)}}
+}
+
+// Undefined returns an UNDEFINED entry.
+func Undefined(tag tifftag.Tag, b []byte) Entry {
+	return Entry{Tag: tag, Type: fieldtype.Undefined, Raw: b}
+}
+
+// Image returns the entries of an uncompressed 8-bit image of the given
+// size, photometric interpretation and number of samples.
+func Image(width, height int, photometric uint64, samples int) []Entry {
+	bits := make([]uint64, samples)
+	for i := range bits {
+		bits[i] = 8
+	}
+	return []Entry{
+		Short(tifftag.ImageWidth, uint64(width)),
+		Short(tifftag.ImageLength, uint64(height)),
+		Short(tifftag.BitsPerSample, bits...),
+		Short(tifftag.Compression, 1),
+		Short(tifftag.PhotometricInterpretation, photometric),
+		Short(tifftag.SamplesPerPixel, uint64(samples)),
+	}
+}
+
+// Gray returns the entries of an uncompressed 8-bit grayscale image stored
+// in a single strip.
+func Gray(width, height int) []Entry {
+	return append(Image(width, height, 1, 1), Short(tifftag.RowsPerStrip, uint64(height)))
+}
+
+// With returns entries with e added, replacing entries of the same tag.
+func With(entries []Entry, e ...Entry) []Entry {
+	out := make([]Entry, 0, len(entries)+len(e))
+	for _, old := range entries {
+		replaced := false
+		for _, n := range e {
+			replaced = replaced || n.Tag == old.Tag
+		}
+		if !replaced {
+			out = append(out, old)
+		}
+	}
+	return append(out, e...)
+}
+
+// Deflate compresses b with zlib, as stored for Compression 8.
+func Deflate(b []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+// Tiles splits a row-major image of the given width and height with
+// samples bytes per pixel into tiles of tw × th pixels in file order,
+// padding partial tiles with zeros.
+func Tiles(pix []byte, width, height, samples, tw, th int) [][]byte {
+	var tiles [][]byte
+	for ty := 0; ty < height; ty += th {
+		for tx := 0; tx < width; tx += tw {
+			tile := make([]byte, tw*th*samples)
+			for y := 0; y < th && ty+y < height; y++ {
+				n := min(tw, width-tx) * samples
+				copy(tile[y*tw*samples:], pix[((ty+y)*width+tx)*samples:][:n])
+			}
+			tiles = append(tiles, tile)
+		}
+	}
+	return tiles
+}
+
+// Bytes encodes the file.
+func (f File) Bytes() []byte {
+	w := &writer{order: binary.LittleEndian, big: f.BigTIFF}
+	if f.ByteOrder != nil {
+		w.order = f.ByteOrder.(byteOrder)
+	}
+	if w.order == binary.LittleEndian {
+		w.out = append(w.out, 'I', 'I')
+	} else {
+		w.out = append(w.out, 'M', 'M')
+	}
+	next := 4
+	if w.big {
+		w.out = w.order.AppendUint16(w.out, 43)
+		w.out = w.order.AppendUint16(w.out, 8)
+		w.out = w.order.AppendUint16(w.out, 0)
+		next = 8
+		w.out = append(w.out, make([]byte, 8)...)
+	} else {
+		w.out = w.order.AppendUint16(w.out, 42)
+		w.out = append(w.out, make([]byte, 4)...)
+	}
+	for _, d := range f.IFDs {
+		pos, nextField := w.ifd(d)
+		w.putOffset(next, uint64(pos))
+		next = nextField
+		if d.Next != 0 {
+			w.putOffset(next, d.Next)
+		}
+	}
+	return w.out
+}
+
+// byteOrder is implemented by binary.LittleEndian and binary.BigEndian.
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// writer appends the parts of a file.
+type writer struct {
+	order byteOrder
+	big   bool
+	out   []byte
+}
+
+// ifd writes d with its blocks and sub-directories and returns its offset
+// and the offset of its next-directory field.
+func (w *writer) ifd(d IFD) (int, int) {
+	offsetType := fieldtype.Long
+	if w.big {
+		offsetType = fieldtype.Long8
+	}
+	entries := append([]Entry(nil), d.Entries...)
+	if d.Blocks != nil {
+		offsets := Entry{Tag: tifftag.StripOffsets, Type: offsetType}
+		counts := Entry{Tag: tifftag.StripByteCounts, Type: offsetType}
+		if d.Tiled {
+			offsets.Tag, counts.Tag = tifftag.TileOffsets, tifftag.TileByteCounts
+		}
+		for _, b := range d.Blocks {
+			offsets.Values = append(offsets.Values, uint64(len(w.out)))
+			counts.Values = append(counts.Values, uint64(len(b)))
+			w.out = append(w.out, b...)
+		}
+		entries = append(entries, offsets, counts)
+	}
+	for tag, sub := range d.SubIFDs {
+		pos, _ := w.ifd(sub)
+		entries = append(entries, Entry{Tag: tag, Type: offsetType, Values: []uint64{uint64(pos)}})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Tag < entries[j].Tag })
+
+	countSize, entrySize, offsetSize := 2, 12, 4
+	if w.big {
+		countSize, entrySize, offsetSize = 8, 20, 8
+	}
+	w.align()
+	pos := len(w.out)
+	w.out = append(w.out, make([]byte, countSize+entrySize*len(entries)+offsetSize)...)
+	w.putUint(pos, countSize, uint64(len(entries)))
+	for i, e := range entries {
+		p := pos + countSize + i*entrySize
+		value, count := w.encode(e)
+		w.order.PutUint16(w.out[p:], uint16(e.Tag))
+		w.order.PutUint16(w.out[p+2:], uint16(e.Type))
+		w.putUint(p+4, offsetSize, count)
+		if len(value) <= offsetSize {
+			copy(w.out[p+4+offsetSize:], value)
+			continue
+		}
+		w.align()
+		w.putOffset(p+4+offsetSize, uint64(len(w.out)))
+		w.out = append(w.out, value...)
+	}
+	return pos, pos + countSize + entrySize*len(entries)
+}
+
+// encode returns the value bytes and count of e.
+func (w *writer) encode(e Entry) ([]byte, uint64) {
+	if e.Values == nil {
+		size := max(e.Type.Size(), 1)
+		return e.Raw, uint64(len(e.Raw) / size)
+	}
+	var b []byte
+	switch e.Type {
+	case fieldtype.Rational, fieldtype.SRational:
+		for _, v := range e.Values {
+			b = w.order.AppendUint32(b, uint32(v))
+		}
+		return b, uint64(len(e.Values) / 2)
+	}
+	for _, v := range e.Values {
+		switch e.Type.Size() {
+		case 1:
+			b = append(b, byte(v))
+		case 2:
+			b = w.order.AppendUint16(b, uint16(v))
+		case 4:
+			b = w.order.AppendUint32(b, uint32(v))
+		default:
+			b = w.order.AppendUint64(b, v)
+		}
+	}
+	return b, uint64(len(e.Values))
+}
+
+// align pads the output to a word boundary.
+func (w *writer) align() {
+	for len(w.out)%2 != 0 {
+		w.out = append(w.out, 0)
+	}
+}
+
+// putOffset stores an offset field at pos.
+func (w *writer) putOffset(pos int, v uint64) {
+	if w.big {
+		w.putUint(pos, 8, v)
+	} else {
+		w.putUint(pos, 4, v)
+	}
+}
+
+// putUint stores an unsigned integer of the given size at pos.
+func (w *writer) putUint(pos, size int, v uint64) {
+	switch size {
+	case 2:
+		w.order.PutUint16(w.out[pos:], uint16(v))
+	case 4:
+		w.order.PutUint32(w.out[pos:], uint32(v))
+	default:
+		w.order.PutUint64(w.out[pos:], v)
+	}
+}
@@ -0,0 +1,128 @@
+// Package iptc decodes IPTC Information Interchange Model (IIM) blocks, as
+// embedded in TIFF files through the IPTC/NAA tag (33723).
+//
+// Reference: https://www.iptc.org/std/IIM/4.2/specification/IIMV4.2.pdf
+package iptc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ErrNotFound is returned by FromDirectory when the image has no IPTC block.
+var ErrNotFound = errors.New("iptc: no embedded block")
+
+// tagMarker starts every dataset of an IIM stream.
+const tagMarker = 0x1c
+
+// Well-known datasets of the application record (record 2).
+const (
+	ObjectName      = 5
+	Urgency         = 10
+	Category        = 15
+	Keywords        = 25
+	DateCreated     = 55
+	TimeCreated     = 60
+	Byline          = 80
+	BylineTitle     = 85
+	City            = 90
+	ProvinceState   = 95
+	CountryCode     = 100
+	CountryName     = 101
+	Headline        = 105
+	Credit          = 110
+	Source          = 115
+	CopyrightNotice = 116
+	Caption         = 120
+	Writer          = 122
+)
+
+// Dataset is a single IIM dataset.
+type Dataset struct {
+	Record uint8
+	ID     uint8
+	Data   []byte
+}
+
+// Block is a decoded IIM stream.
+type Block struct {
+	// Raw holds the IIM stream as embedded in the file.
+	Raw []byte
+
+	// Datasets holds the datasets in stream order.
+	Datasets []Dataset
+}
+
+// Values returns the data of all datasets with the given record and id as
+// strings, e.g. Values(2, iptc.Keywords).
+func (b *Block) Values(record, id uint8) []string {
+	var out []string
+	for _, ds := range b.Datasets {
+		if ds.Record == record && ds.ID == id {
+			out = append(out, string(ds.Data))
+		}
+	}
+	return out
+}
+
+// FromDirectory parses the IPTC block embedded in the image described by d.
+func FromDirectory(d *ifd.Directory) (*Block, error) {
+	raw, ok := d.Bytes(tifftag.IPTCNAA)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return Parse(raw)
+}
+
+// Parse decodes the datasets of an IIM stream. Trailing padding, which TIFF
+// writers add to fill the last LONG of the tag, is ignored.
+func Parse(raw []byte) (*Block, error) {
+	b := &Block{Raw: raw}
+	for i := 0; i < len(raw); {
+		if raw[i] != tagMarker {
+			if allZero(raw[i:]) {
+				break
+			}
+			return nil, fmt.Errorf("iptc: missing tag marker at offset %d", i)
+		}
+		if i+5 > len(raw) {
+			return nil, fmt.Errorf("iptc: truncated dataset header at offset %d", i)
+		}
+		ds := Dataset{Record: raw[i+1], ID: raw[i+2]}
+		size := int(binary.BigEndian.Uint16(raw[i+3:]))
+		i += 5
+		if size&0x8000 != 0 {
+			// Extended dataset: the low bits give the size of the length field.
+			n := size & 0x7fff
+			if n > 4 || i+n > len(raw) {
+				return nil, fmt.Errorf("iptc: invalid extended length at offset %d", i)
+			}
+			size = 0
+			for _, c := range raw[i : i+n] {
+				size = size<<8 | int(c)
+			}
+			i += n
+		}
+		if size < 0 || i+size > len(raw) {
+			return nil, fmt.Errorf("iptc: dataset %d:%d overruns the block", ds.Record, ds.ID)
+		}
+		ds.Data = raw[i : i+size]
+		b.Datasets = append(b.Datasets, ds)
+		i += size
+	}
+	return b, nil
+}
+
+// allZero reports whether b consists of zero bytes only.
+func allZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package iptc
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// dataset encodes a standard dataset.
+func dataset(record, id uint8, data string) []byte {
+	return append([]byte{tagMarker, record, id, byte(len(data) >> 8), byte(len(data))}, data...)
+}
+
+func TestParse(t *testing.T) {
+	long := string(bytes.Repeat([]byte("x"), 300))
+	var raw []byte
+	raw = append(raw, dataset(1, 90, "\x1b%G")...)
+	raw = append(raw, dataset(2, Keywords, "sky")...)
+	raw = append(raw, dataset(2, Keywords, "sea")...)
+	// Extended dataset with a two-byte length field.
+	raw = append(raw, tagMarker, 2, Caption, 0x80, 2, byte(len(long)>>8), byte(len(long)))
+	raw = append(raw, long...)
+	raw = append(raw, 0, 0, 0) // padding to a LONG boundary
+
+	b, err := Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Datasets) != 4 {
+		t.Fatalf("%d datasets, want 4", len(b.Datasets))
+	}
+	if got := b.Values(2, Keywords); !slices.Equal(got, []string{"sky", "sea"}) {
+		t.Errorf("Keywords = %q", got)
+	}
+	if got := b.Values(2, Caption); len(got) != 1 || got[0] != long {
+		t.Errorf("Caption has %d values", len(got))
+	}
+	if got := b.Values(2, City); got != nil {
+		t.Errorf("City = %q", got)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, raw := range map[string][]byte{
+		"missing marker":    {0x1d, 2, 25, 0, 0},
+		"truncated header":  {tagMarker, 2, 25},
+		"overrun":           dataset(2, 25, "abc")[:6],
+		"extended too long": {tagMarker, 2, 25, 0x80, 5, 0, 0, 0, 0, 1},
+	} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("%s: Parse() succeeded", name)
+		}
+	}
+}
+
+func TestFromDirectory(t *testing.T) {
+	raw := dataset(2, ObjectName, "title")
+	entry := tifftest.Entry{Tag: tifftag.IPTCNAA, Type: fieldtype.Undefined, Raw: raw}
+	dirs, err := ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: []tifftest.Entry{entry}})), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := FromDirectory(dirs[0]); err != nil || !slices.Equal(b.Values(2, ObjectName), []string{"title"}) {
+		t.Errorf("FromDirectory() = %v", err)
+	}
+
+	dirs, err = ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(1, 1)})), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromDirectory(dirs[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FromDirectory() = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Package mmap provides read-only access to local files through a memory
+// mapping, so that uncompressed strips and tiles can be decoded without
+// copying them out of the file:
+//
+//	f, err := mmap.Open("large.tif")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	img, err := tiff.Decode(f)
+//
+// Files are mapped on Linux. On other platforms, and for empty files, a File
+// reads through the operating system like *os.File and Bytes returns nil.
+package mmap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrClosed is returned by reads from a closed File.
+var ErrClosed = errors.New("mmap: file closed")
+
+// File is a read-only file opened with Open. Its ReadAt and Bytes methods
+// are safe for concurrent use, also with Close; Read and Seek share one file
+// position.
+type File struct {
+	data []byte   // the mapping, or nil
+	file *os.File // the open file if it is not mapped
+	size int64
+
+	state  sync.RWMutex // held for reading by ReadAt, for writing by Close
+	closed bool
+
+	mu     sync.Mutex // guards offset
+	offset int64
+}
+
+// Open opens the named file for reading and maps it into memory where
+// supported.
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	data, err := mapFile(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("mmap: mapping %s: %w", path, err)
+	}
+	if data == nil {
+		return &File{file: f, size: fi.Size()}, nil
+	}
+	// The mapping stays valid after the descriptor is closed.
+	f.Close()
+	return &File{data: data, size: fi.Size()}, nil
+}
+
+// Size returns the size of the file in bytes.
+func (f *File) Size() int64 {
+	return f.size
+}
+
+// Bytes returns the contents of the mapped file, or nil if the file is not
+// mapped or has been closed. The slice must not be modified and must not be
+// used after Close.
+func (f *File) Bytes() []byte {
+	f.state.RLock()
+	defer f.state.RUnlock()
+	if f.closed {
+		return nil
+	}
+	return f.data
+}
+
+// ReadAt implements io.ReaderAt.
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	f.state.RLock()
+	defer f.state.RUnlock()
+	if f.closed {
+		return 0, ErrClosed
+	}
+	if f.data == nil {
+		return f.file.ReadAt(p, off)
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("mmap: negative offset %d", off)
+	}
+	if off >= f.size {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read implements io.Reader.
+func (f *File) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, fmt.Errorf("mmap: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("mmap: negative position %d", offset)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Close unmaps or closes the file once the ReadAt calls in progress have
+// returned. Reads issued afterwards return ErrClosed. Slices returned by
+// Bytes must no longer be used; images decoded from f must be closed first
+// (see tiff.File), which waits for their pixel reads.
+func (f *File) Close() error {
+	f.state.Lock()
+	defer f.state.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+	f.closed = true
+	if f.data == nil {
+		return f.file.Close()
+	}
+	return unmap(f.data)
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package mmap
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps size bytes of f read-only. Empty files are not mapped.
+func mapFile(f *os.File, size int64) ([]byte, error) {
+	if size <= 0 || int64(int(size)) != size {
+		return nil, nil
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmap releases a mapping created by mapFile.
+func unmap(data []byte) error {
+	return syscall.Munmap(data)
+}
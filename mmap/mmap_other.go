@@ -0,0 +1,15 @@
+//go:build !linux
+
+package mmap
+
+import "os"
+
+// mapFile does not map files on this platform.
+func mapFile(f *os.File, size int64) ([]byte, error) {
+	return nil, nil
+}
+
+// unmap is never called on this platform.
+func unmap(data []byte) error {
+	return nil
+}
@@ -0,0 +1,145 @@
+package mmap
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// testFile writes data to a temporary file and returns its path.
+func testFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadAt(t *testing.T) {
+	data := []byte("0123456789")
+	f, err := Open(testFile(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if f.Size() != int64(len(data)) {
+		t.Errorf("Size() = %d, want %d", f.Size(), len(data))
+	}
+	if b := f.Bytes(); runtime.GOOS == "linux" && !bytes.Equal(b, data) {
+		t.Errorf("Bytes() = %q, want %q", b, data)
+	}
+
+	tests := []struct {
+		off, n  int
+		want    string
+		wantErr error
+	}{
+		{0, 4, "0123", nil},
+		{6, 4, "6789", nil},
+		{8, 4, "89", io.EOF},
+		{10, 1, "", io.EOF},
+	}
+	for _, tt := range tests {
+		p := make([]byte, tt.n)
+		n, err := f.ReadAt(p, int64(tt.off))
+		if string(p[:n]) != tt.want || err != tt.wantErr {
+			t.Errorf("ReadAt(%d bytes at %d) = %q, %v, want %q, %v", tt.n, tt.off, p[:n], err, tt.want, tt.wantErr)
+		}
+	}
+	if _, err := f.ReadAt(make([]byte, 1), -1); err == nil {
+		t.Errorf("ReadAt at a negative offset succeeded")
+	}
+}
+
+func TestReadSeek(t *testing.T) {
+	data := []byte("0123456789")
+	f, err := Open(testFile(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(-4, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(f)
+	if err != nil || string(got) != "6789" {
+		t.Errorf("ReadAll after Seek = %q, %v, want %q", got, err, "6789")
+	}
+	if _, err := f.Seek(-1, io.SeekStart); err == nil {
+		t.Errorf("Seek to a negative position succeeded")
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	f, err := Open(testFile(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if f.Bytes() != nil {
+		t.Errorf("Bytes() of an empty file = %v, want nil", f.Bytes())
+	}
+	if _, err := f.ReadAt(make([]byte, 1), 0); err != io.EOF {
+		t.Errorf("ReadAt() = %v, want io.EOF", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	f, err := Open(testFile(t, []byte("0123456789")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.ReadAt(make([]byte, 1), 0); !errors.Is(err, ErrClosed) {
+		t.Errorf("ReadAt() after Close = %v, want ErrClosed", err)
+	}
+	if f.Bytes() != nil {
+		t.Errorf("Bytes() after Close = %v, want nil", f.Bytes())
+	}
+	if err := f.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("second Close() = %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseDuringReads(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 1<<20)
+	for i := 0; i < 20; i++ {
+		f, err := Open(testFile(t, data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p := make([]byte, len(data))
+				for {
+					n, err := f.ReadAt(p, 0)
+					if errors.Is(err, ErrClosed) {
+						return
+					}
+					if err != nil || n != len(p) || p[n-1] != 1 {
+						t.Errorf("ReadAt() = %d, %v", n, err)
+						return
+					}
+				}
+			}()
+		}
+		runtime.Gosched()
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,71 @@
+package tiff
+
+import "github.com/echoflaresat/tiff/blockcache"
+
+// Options configures DecodeWithOptions.
+// The zero value gives the behavior of Decode.
+type Options struct {
+	// AutoOrient presents the image in its display orientation according to
+	// the Orientation tag (274). Width and height are swapped for rotated
+	// orientations and coordinates are remapped lazily on each access, so no
+	// pixel data is materialized.
+	AutoOrient bool
+
+	// NoDataTransparent renders pixels whose displayed bands all equal the
+	// GDAL nodata value (GDAL_NODATA tag) as fully transparent. It has no
+	// effect on images without the tag or decoded by the fallback decoder.
+	NoDataTransparent bool
+
+	// Page selects the page of a multi-page file, counting from 0. Reduced
+	// resolution images and transparency masks are not counted as pages.
+	Page int
+
+	// Overview selects a reduced-resolution image (overview) of the page,
+	// counting from 1 in file order, as written by GDAL for pyramids.
+	// 0 selects the full-resolution image.
+	Overview int
+
+	// Cache holds the decoded strips or tiles of the image. Images sharing a
+	// cache share its byte budget. If nil, blockcache.Default is used, unless
+	// CacheBytes is set.
+	Cache *blockcache.Cache
+
+	// CacheBytes gives the image a private cache with this byte budget when
+	// Cache is nil. The most recently decoded block is always kept.
+	CacheBytes int64
+
+	// NoFallback returns the error of the random-access loaders instead of
+	// decoding unsupported files with golang.org/x/image/tiff. There is never
+	// a fallback for pages or overviews other than the first image.
+	NoFallback bool
+
+	// Strict rejects files whose selected directory violates the TIFF
+	// specification: unsorted or duplicate entries, field types or value
+	// counts not allowed for a tag (see tifftag.Lookup) and missing required
+	// tags. By default such files are decoded leniently where possible.
+	Strict bool
+
+	// CoalesceGap and CoalesceMaxBytes control how Image.ReadRegion fetches
+	// uncached strips and tiles: blocks at most CoalesceGap bytes apart in
+	// the file are read together, in reads of up to CoalesceMaxBytes. This
+	// saves round trips on remote or high-latency readers. 0 selects 32 KiB
+	// and 4 MiB; a negative CoalesceGap reads every block separately.
+	CoalesceGap      int64
+	CoalesceMaxBytes int64
+
+	// ReadAhead enables sequential access: each time a strip or tile is
+	// read, the ReadAhead blocks following it in the file are loaded into
+	// the cache in the background, so scans through At do not stall at
+	// block boundaries. 0 disables read-ahead.
+	ReadAhead int
+
+	// SpoolThreshold is the size up to which inputs that implement neither
+	// io.ReaderAt nor io.ReadSeeker are buffered in memory; larger inputs
+	// are copied to a temporary file so they can be read lazily as well.
+	// 0 selects DefaultSpoolThreshold and a negative value always buffers
+	// in memory.
+	SpoolThreshold int64
+
+	// SpoolDir is the directory of spool files; empty selects os.TempDir().
+	SpoolDir string
+}
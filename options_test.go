@@ -0,0 +1,115 @@
+package tiff_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+	"github.com/echoflaresat/tiff/blockcache"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestPageAndOverview(t *testing.T) {
+	data := pyramid()
+	tests := []struct {
+		name    string
+		opts    tiff.Options
+		bounds  image.Rectangle
+		wantErr bool
+	}{
+		{"first page", tiff.Options{}, image.Rect(0, 0, 4, 4), false},
+		{"overview", tiff.Options{Overview: 1}, image.Rect(0, 0, 2, 2), false},
+		{"second page", tiff.Options{Page: 1}, image.Rect(0, 0, 1, 1), false},
+		{"missing overview", tiff.Options{Overview: 2}, image.Rectangle{}, true},
+		{"missing page", tiff.Options{Page: 2}, image.Rectangle{}, true},
+		{"overview of second page", tiff.Options{Page: 1, Overview: 1}, image.Rectangle{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := tiff.DecodeWithOptions(bytes.NewReader(data), tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if img.Bounds() != tt.bounds {
+				t.Errorf("Bounds() = %v, want %v", img.Bounds(), tt.bounds)
+			}
+		})
+	}
+}
+
+func TestNoFallback(t *testing.T) {
+	// WhiteIsZero is decoded by golang.org/x/image/tiff only.
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Image(2, 2, 0, 1), tifftest.Short(tifftag.RowsPerStrip, 2)),
+		Blocks:  [][]byte{{0, 1, 2, 3}},
+	})
+
+	img, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, lazy := img.(tiff.Image); lazy {
+		t.Errorf("Decode returned a lazy image, want the fallback decoder's")
+	}
+
+	if _, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{NoFallback: true}); err == nil {
+		t.Errorf("DecodeWithOptions(NoFallback) succeeded, want error")
+	}
+}
+
+func TestStrict(t *testing.T) {
+	// Orientation must have exactly one value.
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(2, 2), tifftest.Short(tifftag.Orientation, 1, 1)),
+		Blocks:  [][]byte{{0, 1, 2, 3}},
+	})
+
+	if _, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{}); err != nil {
+		t.Errorf("lenient DecodeWithOptions() = %v", err)
+	}
+	_, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{Strict: true})
+	if !errors.Is(err, ifd.ErrInvalidDirectory) {
+		t.Errorf("strict DecodeWithOptions() = %v, want ErrInvalidDirectory", err)
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	// Two strips of 4 bytes.
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(4, 2), tifftest.Short(tifftag.RowsPerStrip, 1)),
+		Blocks:  [][]byte{{1, 2, 3, 4}, {5, 6, 7, 8}},
+	})
+	scan := func(img image.Image) blockcache.Stats {
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 4; x++ {
+				img.At(x, y)
+			}
+		}
+		return img.(tiff.Image).CacheStats()
+	}
+
+	img, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{CacheBytes: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := scan(img); s.Blocks != 1 || s.Evictions != 1 {
+		t.Errorf("CacheBytes 4: %+v, want 1 block and 1 eviction", s)
+	}
+
+	cache := blockcache.New(1 << 20)
+	img, err = tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{Cache: cache, CacheBytes: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scan(img)
+	if s := cache.Stats(); s.Blocks != 2 || s.Bytes != 8 {
+		t.Errorf("shared cache: %+v, want 2 blocks of 8 bytes", s)
+	}
+}
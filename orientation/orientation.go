@@ -0,0 +1,128 @@
+// Package orientation defines the TIFF Orientation tag values, which describe
+// how the stored rows and columns map to the visual top and left of the image.
+//
+// This corresponds to TIFF tag 274:
+// https://www.awaresystems.be/imaging/tiff/tifftags/orientation.html
+package orientation
+
+import "fmt"
+
+// Type represents the TIFF Orientation field (tag 274).
+// The name of each value gives the visual position of the stored row 0
+// followed by the visual position of the stored column 0.
+type Type int
+
+const (
+	// TopLeft (1): row 0 is the visual top, column 0 the visual left. This is the default.
+	TopLeft Type = 1
+
+	// TopRight (2): row 0 is the visual top, column 0 the visual right (mirrored horizontally).
+	TopRight Type = 2
+
+	// BottomRight (3): row 0 is the visual bottom, column 0 the visual right (rotated 180°).
+	BottomRight Type = 3
+
+	// BottomLeft (4): row 0 is the visual bottom, column 0 the visual left (mirrored vertically).
+	BottomLeft Type = 4
+
+	// LeftTop (5): row 0 is the visual left, column 0 the visual top (transposed).
+	LeftTop Type = 5
+
+	// RightTop (6): row 0 is the visual right, column 0 the visual top (rotated 90° clockwise).
+	RightTop Type = 6
+
+	// RightBottom (7): row 0 is the visual right, column 0 the visual bottom (transversed).
+	RightBottom Type = 7
+
+	// LeftBottom (8): row 0 is the visual left, column 0 the visual bottom (rotated 90° counter-clockwise).
+	LeftBottom Type = 8
+)
+
+// Valid reports whether o is one of the eight orientations defined by the specification.
+func (o Type) Valid() bool {
+	return o >= TopLeft && o <= LeftBottom
+}
+
+// Transposed reports whether o swaps rows and columns, i.e. whether the
+// displayed width is the stored height.
+func (o Type) Transposed() bool {
+	return o >= LeftTop && o <= LeftBottom
+}
+
+// Size returns the displayed width and height of an image stored as w×h pixels.
+func (o Type) Size(w, h int) (int, int) {
+	if o.Transposed() {
+		return h, w
+	}
+	return w, h
+}
+
+// ToStored maps the displayed pixel (x, y) to the stored pixel of an image
+// stored as w×h pixels. Invalid orientations are treated as TopLeft.
+func (o Type) ToStored(x, y, w, h int) (int, int) {
+	switch o {
+	case TopRight:
+		return w - 1 - x, y
+	case BottomRight:
+		return w - 1 - x, h - 1 - y
+	case BottomLeft:
+		return x, h - 1 - y
+	case LeftTop:
+		return y, x
+	case RightTop:
+		return y, h - 1 - x
+	case RightBottom:
+		return w - 1 - y, h - 1 - x
+	case LeftBottom:
+		return w - 1 - y, x
+	default:
+		return x, y
+	}
+}
+
+// FromStored maps the stored pixel (x, y) of an image stored as w×h pixels
+// to the displayed pixel. It is the inverse of ToStored.
+func (o Type) FromStored(x, y, w, h int) (int, int) {
+	switch o {
+	case TopRight:
+		return w - 1 - x, y
+	case BottomRight:
+		return w - 1 - x, h - 1 - y
+	case BottomLeft:
+		return x, h - 1 - y
+	case LeftTop:
+		return y, x
+	case RightTop:
+		return h - 1 - y, x
+	case RightBottom:
+		return h - 1 - y, w - 1 - x
+	case LeftBottom:
+		return y, w - 1 - x
+	default:
+		return x, y
+	}
+}
+
+// String returns the symbolic name of the orientation.
+func (o Type) String() string {
+	switch o {
+	case TopLeft:
+		return "TopLeft"
+	case TopRight:
+		return "TopRight"
+	case BottomRight:
+		return "BottomRight"
+	case BottomLeft:
+		return "BottomLeft"
+	case LeftTop:
+		return "LeftTop"
+	case RightTop:
+		return "RightTop"
+	case RightBottom:
+		return "RightBottom"
+	case LeftBottom:
+		return "LeftBottom"
+	default:
+		return fmt.Sprintf("Orientation(%d)", int(o))
+	}
+}
@@ -0,0 +1,62 @@
+package orientation
+
+import "testing"
+
+// display returns the stored pixel shown at (x, y) for an image stored as
+// w×h pixels, derived from the tag definitions.
+func display(o Type, x, y, w, h int) (int, int) {
+	switch o {
+	case TopRight:
+		return w - 1 - x, y
+	case BottomRight:
+		return w - 1 - x, h - 1 - y
+	case BottomLeft:
+		return x, h - 1 - y
+	case LeftTop:
+		return y, x
+	case RightTop:
+		return y, h - 1 - x
+	case RightBottom:
+		return w - 1 - y, h - 1 - x
+	case LeftBottom:
+		return w - 1 - y, x
+	default:
+		return x, y
+	}
+}
+
+func TestMapping(t *testing.T) {
+	const w, h = 3, 2
+	for o := TopLeft; o <= LeftBottom; o++ {
+		dw, dh := o.Size(w, h)
+		if o.Transposed() != (dw == h && dh == w && o >= LeftTop) {
+			t.Errorf("%v: Size(%d, %d) = %d, %d", o, w, h, dw, dh)
+		}
+		for y := 0; y < dh; y++ {
+			for x := 0; x < dw; x++ {
+				wx, wy := display(o, x, y, w, h)
+				sx, sy := o.ToStored(x, y, w, h)
+				if sx != wx || sy != wy {
+					t.Errorf("%v: ToStored(%d, %d) = %d, %d, want %d, %d", o, x, y, sx, sy, wx, wy)
+				}
+				if bx, by := o.FromStored(sx, sy, w, h); bx != x || by != y {
+					t.Errorf("%v: FromStored(%d, %d) = %d, %d, want %d, %d", o, sx, sy, bx, by, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, o := range []Type{0, 9, -1} {
+		if o.Valid() {
+			t.Errorf("Type(%d).Valid() = true", o)
+		}
+		if x, y := o.ToStored(1, 0, 3, 2); x != 1 || y != 0 {
+			t.Errorf("Type(%d).ToStored(1, 0) = %d, %d, want identity", o, x, y)
+		}
+	}
+	if got := RightTop.String(); got != "RightTop" {
+		t.Errorf("RightTop.String() = %q", got)
+	}
+}
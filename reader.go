@@ -12,82 +12,163 @@
 //
 // Supported features in random access mode:
 //
-//   - Striped and Tiled TIFF decoding
+//   - Striped and Tiled TIFF decoding, classic TIFF and BigTIFF
 //   - Compression: None, Deflate (zlib)
 //   - Photometric: RGB, BlackIsZero (grayscale)
 //   - PlanarConfig: Contig (interleaved samples only)
+//   - ExtraSamples: associated (RGBA) and unassociated (NRGBA) alpha
+//   - Any number of bands per pixel, accessible through the Image interface
+//   - Transparency mask subfiles (NewSubfileType mask, 1-bit TransMask), applied to alpha
 //
 // Example usage:
 //
+//	f, err := os.Open("image.tif") // Must remain open when using the image
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	img, err := tiff.Decode(f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// Use img.At(x, y), img.Bounds(), etc.
+//
+// Importing the package also registers the "tiff" format (classic TIFF and
+// BigTIFF) with the image package, ahead of golang.org/x/image/tiff:
+//
 //	import (
 //	    "image"
 //	    _ "github.com/echoflaresat/tiff"
 //	)
 //
-//	func main() {
-//	    f, _ := os.Open("image.tif") // Must remain open when using the image
-//	    defer f.Close()
+//	img, _, err := image.Decode(f)
 //
-//	    img, _, err := image.Decode(f)
-//	    if err != nil {
-//	        log.Fatal(err)
-//	    }
-//
-//	    // Use img.At(x, y), img.Bounds(), etc.
-//	}
+// image.Decode hides the io.ReaderAt of f behind a buffered reader, so the
+// file is read into memory first; call Decode directly to read pixel data on
+// demand. SetPrecedence hands classic TIFF files back to golang.org/x/image/tiff.
 //
 // For full details and source, visit: https://pkg.go.dev/github.com/echoflaresat/tiff
 package tiff
 
 import (
+	"context"
+	"errors"
 	"image"
 	"io"
+	"math"
 
+	"github.com/echoflaresat/tiff/ifd"
 	"github.com/echoflaresat/tiff/impl"
+	"github.com/echoflaresat/tiff/orientation"
+	"github.com/echoflaresat/tiff/tifftag"
 	stdtiff "golang.org/x/image/tiff"
 )
 
-// DecodeConfig returns the color model and dimensions of a TIFF image without decoding the entire image.
-// It uses the standard library's TIFF decoder for configuration extraction.
-func DecodeConfig(r io.Reader) (image.Config, error) {
-	return stdtiff.DecodeConfig(r)
-}
-
 // Decode reads a TIFF image from r and returns it as an image.Image.
 // It first attempts to decode using custom striped and tiled TIFF loaders,
 // falling back to the standard library's TIFF decoder if those fail.
+//
+// Pixel data is read on demand from r if it implements io.ReaderAt or
+// io.ReadSeeker, so r must stay open until the returned image is closed (see
+// Image.Close) or no longer used; Open manages both for files. Other
+// readers, such as the buffered reader image.Decode passes, are first
+// buffered in memory or, beyond Options.SpoolThreshold, spooled to a
+// temporary file that is removed by Image.Close.
+//
+// Decode is equivalent to DecodeWithOptions with the zero Options.
 func Decode(r io.Reader) (image.Image, error) {
-	var readerAt io.ReaderAt
+	return DecodeWithOptions(r, Options{})
+}
+
+// DecodeWithOptions reads a TIFF image from r like Decode, applying opts.
+func DecodeWithOptions(r io.Reader, opts Options) (image.Image, error) {
+	return DecodeContext(context.Background(), r, opts)
+}
 
-	if ra, ok := r.(io.ReaderAt); ok {
-		readerAt = ra
-	} else if rs, ok := r.(io.ReadSeeker); ok {
-		readerAt = &readerAtFromSeeker{rs: rs}
+// DecodeContext is DecodeWithOptions with the directories (and, for the
+// fallback decoder, the whole image) read on behalf of ctx. Readers that
+// implement ReaderAtContext receive ctx with every read.
+//
+// ctx only governs decoding; pixel data of the returned image is read on
+// behalf of the contexts given to Image.AtContext and Image.ReadRegion.
+func DecodeContext(ctx context.Context, r io.Reader, opts Options) (image.Image, error) {
+	readerAt, owned, err := readerAtFor(r, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	if readerAt != nil {
-		if img, err := impl.LoadStripedTiff(readerAt); err == nil {
-			return img, nil
-		}
-		if img, err := impl.LoadTiledTiff(readerAt); err == nil {
-			return img, nil
-		}
+	img, err := decode(ctx, readerAt, owned, opts)
+	if _, lazy := img.(Image); owned != nil && !lazy {
+		// Only lazy images keep reading the spooled input; they close it.
+		defer owned.Close()
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Fallback to standard decoder
-	return stdtiff.Decode(r)
+	if opts.NoDataTransparent {
+		img = impl.MaskNoData(img)
+	}
+	if opts.AutoOrient {
+		o, err := orientationOf(img, impl.WithContext(ctx, readerAt))
+		if err != nil {
+			return nil, err
+		}
+		img = impl.Orient(img, o)
+	}
+	return img, nil
 }
 
-// readerAtFromSeeker adapts an io.ReadSeeker to io.ReaderAt.
-type readerAtFromSeeker struct {
-	rs io.ReadSeeker
+// decode loads the directory selected by opts with the random-access loaders
+// and falls back to the standard decoder if allowed. The lazy image closes
+// owned, if not nil, when it is closed.
+//
+// The standard decoder only reads the first directory, so there is no
+// fallback for other pages or overviews, and none for files rejected by
+// strict validation.
+func decode(ctx context.Context, readerAt io.ReaderAt, owned io.Closer, opts Options) (image.Image, error) {
+	img, err := impl.Load(ctx, readerAt, impl.LoadOptions{
+		Page:             opts.Page,
+		Overview:         opts.Overview,
+		Cache:            opts.Cache,
+		CacheBytes:       opts.CacheBytes,
+		Strict:           opts.Strict,
+		CoalesceGap:      opts.CoalesceGap,
+		CoalesceMaxBytes: opts.CoalesceMaxBytes,
+		ReadAhead:        opts.ReadAhead,
+		Owned:            owned,
+	})
+	if err == nil {
+		return img, nil
+	}
+	if opts.NoFallback || opts.Page != 0 || opts.Overview != 0 || errors.Is(err, ifd.ErrInvalidDirectory) {
+		return nil, err
+	}
+
+	// Fallback to standard decoder. It reads through io.ReaderAt when
+	// available, so the section reader is never consumed sequentially.
+	return stdtiff.Decode(io.NewSectionReader(impl.WithContext(ctx, readerAt), 0, math.MaxInt64))
 }
 
-// ReadAt implements the io.ReaderAt interface for readerAtFromSeeker.
-// It seeks to the specified offset and reads into p.
-func (r *readerAtFromSeeker) ReadAt(p []byte, off int64) (int, error) {
-	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
-		return 0, err
+// orientationOf returns the Orientation tag of the directory img was decoded
+// from. Images of the fallback decoder always come from the first directory.
+func orientationOf(img image.Image, readerAt io.ReaderAt) (orientation.Type, error) {
+	if ti, ok := img.(Image); ok {
+		if v, ok := ti.Directory().Uint(tifftag.Orientation); ok {
+			return orientation.Type(v), nil
+		}
+		return orientation.TopLeft, nil
 	}
-	return r.rs.Read(p)
+	return impl.ReadOrientation(readerAt)
+}
+
+// ReaderAtContext is implemented by readers that can abandon a read when its
+// context is cancelled, such as network backends. When the io.Reader given to
+// DecodeContext implements it, directory and pixel reads issued on behalf of
+// a context use ReadAtContext instead of ReadAt.
+type ReaderAtContext interface {
+	io.ReaderAt
+	ReadAtContext(ctx context.Context, p []byte, off int64) (n int, err error)
 }
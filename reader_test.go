@@ -0,0 +1,130 @@
+package tiff_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+func TestDecodeSeparatePlanes(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Image(2, 1, 2, 3),
+			tifftest.Short(tifftag.PlanarConfiguration, 2),
+			tifftest.Short(tifftag.RowsPerStrip, 1)),
+		Blocks: [][]byte{{0, 10}, {100, 110}, {200, 210}},
+	})
+
+	d, err := tiff.DescribeImage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Lazy {
+		t.Errorf("Lazy = true for separate planes, want false")
+	}
+	if _, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{NoFallback: true}); err == nil {
+		t.Errorf("DecodeWithOptions(NoFallback) succeeded on separate planes")
+	}
+}
+
+func TestDecodeAlpha(t *testing.T) {
+	// One RGBA pixel: 8-bit samples are read lazily, 16-bit ones fall back
+	// to golang.org/x/image/tiff.
+	px8 := []byte{200, 100, 50, 128}
+	px16 := []byte{0x00, 0x60, 0x00, 0x40, 0x00, 0x20, 0x00, 0x80} // little-endian
+	tests := []struct {
+		name  string
+		bits  uint64
+		extra uint64
+		want  color.Color // nil if the file is rejected
+	}{
+		{"8-bit unspecified", 8, 0, color.RGBA{200, 100, 50, 255}},
+		{"8-bit associated", 8, 1, color.RGBA{128, 100, 50, 128}}, // red clamped to alpha
+		{"8-bit unassociated", 8, 2, color.NRGBA{200, 100, 50, 128}},
+		{"16-bit unspecified", 16, 0, nil},
+		{"16-bit associated", 16, 1, color.RGBA64{0x6000, 0x4000, 0x2000, 0x8000}},
+		{"16-bit unassociated", 16, 2, color.NRGBA64{0x6000, 0x4000, 0x2000, 0x8000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			px := px8
+			if tt.bits == 16 {
+				px = px16
+			}
+			data := tifftest.Build(tifftest.IFD{
+				Entries: tifftest.With(tifftest.Image(1, 1, 2, 4),
+					tifftest.Short(tifftag.BitsPerSample, tt.bits, tt.bits, tt.bits, tt.bits),
+					tifftest.Short(tifftag.ExtraSamples, tt.extra),
+					tifftest.Short(tifftag.RowsPerStrip, 1)),
+				Blocks: [][]byte{px},
+			})
+			img, err := tiff.Decode(bytes.NewReader(data))
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("Decode() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, lazy := img.(tiff.Image); lazy != (tt.bits == 8) {
+				t.Errorf("Decode returned %T", img)
+			}
+			if got := img.At(0, 0); got != tt.want {
+				t.Errorf("At(0, 0) = %#v, want %#v", got, tt.want)
+			}
+			if got, want := img.ColorModel(), tt.want; got.Convert(want) != want {
+				t.Errorf("ColorModel() does not preserve %#v", want)
+			}
+		})
+	}
+}
+
+func TestDecodeCorruptTrailingDirectory(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.Gray(2, 2),
+		Blocks:  [][]byte{{1, 2, 3, 4}},
+		Next:    1 << 20,
+	})
+	img, err := tiff.DecodeWithOptions(bytes.NewReader(data), tiff.Options{NoFallback: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := img.(tiff.Image); !ok {
+		t.Fatalf("Decode returned %T, want a lazy tiff.Image", img)
+	}
+	if got, want := img.At(1, 1), (color.RGBA{4, 4, 4, 255}); got != want {
+		t.Errorf("At(1, 1) = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeAutoOrient(t *testing.T) {
+	data := tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(3, 2), tifftest.Short(tifftag.Orientation, 8)),
+		Blocks:  [][]byte{{1, 2, 3, 4, 5, 6}},
+	})
+	for _, tt := range []struct {
+		opts   tiff.Options
+		bounds image.Rectangle
+		at     color.Color
+	}{
+		{tiff.Options{}, image.Rect(0, 0, 3, 2), color.RGBA{1, 1, 1, 255}},
+		{tiff.Options{AutoOrient: true}, image.Rect(0, 0, 2, 3), color.RGBA{3, 3, 3, 255}},
+	} {
+		img, err := tiff.DecodeWithOptions(bytes.NewReader(data), tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds() != tt.bounds {
+			t.Errorf("AutoOrient %v: Bounds() = %v, want %v", tt.opts.AutoOrient, img.Bounds(), tt.bounds)
+		}
+		if got := img.At(0, 0); got != tt.at {
+			t.Errorf("AutoOrient %v: At(0, 0) = %v, want %v", tt.opts.AutoOrient, got, tt.at)
+		}
+	}
+}
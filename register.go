@@ -0,0 +1,73 @@
+package tiff
+
+import (
+	"image"
+	"io"
+	"sync/atomic"
+
+	"github.com/echoflaresat/tiff/internal/register"
+	stdtiff "golang.org/x/image/tiff"
+)
+
+// Precedence selects the decoder image.Decode and image.DecodeConfig use
+// for TIFF files.
+//
+// Importing this package registers the "tiff" format ahead of
+// golang.org/x/image/tiff, for the classic TIFF ("II*\x00", "MM\x00*") and
+// BigTIFF ("II+\x00", "MM\x00+") magic strings.
+type Precedence int32
+
+const (
+	// PreferLazy decodes TIFF files with this package. It is the default.
+	PreferLazy Precedence = iota
+
+	// PreferStandard decodes classic TIFF files with golang.org/x/image/tiff,
+	// as if this package were not registered. BigTIFF files, which the
+	// standard decoder cannot read, are still decoded by this package.
+	PreferStandard
+)
+
+// precedence holds the current Precedence.
+var precedence atomic.Int32
+
+// SetPrecedence selects the decoder used by image.Decode and
+// image.DecodeConfig for TIFF files. It is safe for concurrent use.
+func SetPrecedence(p Precedence) {
+	precedence.Store(int32(p))
+}
+
+func init() {
+	register.Decode = decodeRegistered
+	register.DecodeConfig = decodeConfigRegistered
+}
+
+// decodeRegistered is the decoder registered with the image package.
+func decodeRegistered(r io.Reader) (image.Image, error) {
+	if useStandard(r) {
+		return stdtiff.Decode(r)
+	}
+	return Decode(r)
+}
+
+// decodeConfigRegistered is the config decoder registered with the image package.
+func decodeConfigRegistered(r io.Reader) (image.Config, error) {
+	if useStandard(r) {
+		return stdtiff.DecodeConfig(r)
+	}
+	return DecodeConfig(r)
+}
+
+// useStandard reports whether r should be handed to the standard decoder.
+// image.Decode passes a reader that supports Peek, which is used to keep
+// BigTIFF files with this package.
+func useStandard(r io.Reader) bool {
+	if Precedence(precedence.Load()) != PreferStandard {
+		return false
+	}
+	if p, ok := r.(interface{ Peek(int) ([]byte, error) }); ok {
+		if magic, err := p.Peek(4); err == nil && (magic[2] == '+' || magic[3] == '+') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package tiff_test
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+)
+
+func TestRegisteredDecoder(t *testing.T) {
+	gray := tifftest.IFD{Entries: tifftest.Gray(3, 2), Blocks: [][]byte{{1, 2, 3, 4, 5, 6}}}
+	classic := tifftest.Build(gray)
+	big := tifftest.File{BigTIFF: true, IFDs: []tifftest.IFD{gray}}.Bytes()
+	defer tiff.SetPrecedence(tiff.PreferLazy)
+
+	tests := []struct {
+		name       string
+		precedence tiff.Precedence
+		data       []byte
+		lazy       bool
+	}{
+		{"lazy classic", tiff.PreferLazy, classic, true},
+		{"lazy BigTIFF", tiff.PreferLazy, big, true},
+		{"standard classic", tiff.PreferStandard, classic, false},
+		{"standard BigTIFF", tiff.PreferStandard, big, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiff.SetPrecedence(tt.precedence)
+			img, format, err := image.Decode(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "tiff" {
+				t.Errorf("format = %q, want tiff", format)
+			}
+			if _, ok := img.(tiff.Image); ok != tt.lazy {
+				t.Errorf("image.Decode returned %T, lazy = %v, want %v", img, ok, tt.lazy)
+			}
+			if got, want := img.Bounds(), image.Rect(0, 0, 3, 2); got != want {
+				t.Errorf("Bounds() = %v, want %v", got, want)
+			}
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "tiff" || cfg.Width != 3 || cfg.Height != 2 {
+				t.Errorf("image.DecodeConfig = %+v, %q, want 3×2 tiff", cfg, format)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package tiff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// DefaultSpoolThreshold is the size up to which inputs that are neither
+// io.ReaderAt nor io.ReadSeeker are buffered in memory when
+// Options.SpoolThreshold is 0.
+const DefaultSpoolThreshold = 32 << 20
+
+// readerAtFor returns r as an io.ReaderAt, adapting io.ReadSeeker if needed.
+// Readers supporting neither are buffered according to opts, since
+// directories and pixel data may be located anywhere in the file.
+//
+// If r is spooled to a temporary file, the file is returned as owned and
+// must be closed by the caller once it is no longer read.
+func readerAtFor(r io.Reader, opts Options) (ra io.ReaderAt, owned io.Closer, err error) {
+	if ra, ok := r.(io.ReaderAt); ok {
+		return ra, nil, nil
+	}
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return &readerAtFromSeeker{rs: rs, mu: &sync.Mutex{}}, nil, nil
+	}
+
+	threshold := opts.SpoolThreshold
+	if threshold == 0 {
+		threshold = DefaultSpoolThreshold
+	}
+	if threshold < 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bytes.NewReader(data), nil, nil
+	}
+	return spool(r, threshold, opts.SpoolDir)
+}
+
+// directoryReaderAt is readerAtFor for callers that only read directories,
+// such as DecodeConfig. Inputs that are neither io.ReaderAt nor
+// io.ReadSeeker are read on demand, so that the header and the directories
+// near the start of the file are parsed without reading the rest; the input
+// is only spooled if a read reaches beyond the spool threshold.
+//
+// The returned closer releases the spooled file, if any, and must be called
+// once the reader is no longer used.
+func directoryReaderAt(r io.Reader, opts Options) (io.ReaderAt, io.Closer, error) {
+	switch r.(type) {
+	case io.ReaderAt, io.ReadSeeker:
+		return readerAtFor(r, opts)
+	}
+	threshold := opts.SpoolThreshold
+	if threshold == 0 {
+		threshold = DefaultSpoolThreshold
+	}
+	if threshold < 0 {
+		threshold = math.MaxInt64
+	}
+	s := &streamReaderAt{r: r, threshold: threshold, dir: opts.SpoolDir}
+	return s, s, nil
+}
+
+// streamReadSize is the minimum number of bytes a streamReaderAt reads from
+// its input at once.
+const streamReadSize = 64 << 10
+
+// streamReaderAt gives random access to the start of a plain io.Reader. The
+// input is read as far as reads require and buffered in memory up to
+// threshold bytes; a read beyond that spools the whole input like Decode.
+type streamReaderAt struct {
+	mu        sync.Mutex
+	r         io.Reader
+	threshold int64
+	dir       string
+	buf       []byte
+	eof       bool        // the input has been read completely into buf
+	spooled   io.ReaderAt // the spooled input, once a read went past threshold
+	owned     io.Closer
+}
+
+// ReadAt implements io.ReaderAt.
+func (s *streamReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+	if s.spooled == nil && !s.eof && off+int64(len(p)) > int64(len(s.buf)) {
+		if err := s.fill(off + int64(len(p))); err != nil {
+			return 0, err
+		}
+	}
+	if s.spooled != nil {
+		return s.spooled.ReadAt(p, off)
+	}
+	return bytes.NewReader(s.buf).ReadAt(p, off)
+}
+
+// fill reads the input up to end bytes, or spools it if end lies beyond the
+// threshold.
+func (s *streamReaderAt) fill(end int64) error {
+	if end > s.threshold || end < 0 {
+		ra, owned, err := spool(io.MultiReader(bytes.NewReader(s.buf), s.r), s.threshold, s.dir)
+		if err != nil {
+			return err
+		}
+		s.spooled, s.owned, s.buf = ra, owned, nil
+		return nil
+	}
+	end = min(max(end, int64(len(s.buf))+streamReadSize), s.threshold)
+	more := make([]byte, end-int64(len(s.buf)))
+	n, err := io.ReadFull(s.r, more)
+	s.buf = append(s.buf, more[:n]...)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		s.eof = true
+		return nil
+	}
+	return err
+}
+
+// Close releases the spooled input, if any.
+func (s *streamReaderAt) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.owned != nil {
+		return s.owned.Close()
+	}
+	return nil
+}
+
+// spool buffers r in memory if it holds at most threshold bytes and copies it
+// to a temporary file in dir otherwise.
+//
+// The file is returned as owned. It is unlinked right after it is created
+// where the platform allows it, so it disappears once it is closed;
+// elsewhere it is removed on close.
+func spool(r io.Reader, threshold int64, dir string) (io.ReaderAt, io.Closer, error) {
+	head, err := io.ReadAll(io.LimitReader(r, threshold+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(head)) <= threshold {
+		return bytes.NewReader(head), nil, nil
+	}
+
+	f, err := os.CreateTemp(dir, "tiff-spool-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("spooling input: %w", err)
+	}
+	sf := &spoolFile{File: f, removed: os.Remove(f.Name()) == nil}
+	if _, err := f.Write(head); err != nil {
+		sf.Close()
+		return nil, nil, fmt.Errorf("spooling input: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		sf.Close()
+		return nil, nil, fmt.Errorf("spooling input: %w", err)
+	}
+	return f, sf, nil
+}
+
+// spoolFile is a temporary file holding a spooled input.
+type spoolFile struct {
+	*os.File
+	removed bool // unlinked while open
+}
+
+// Close closes the file and removes it unless it has been unlinked already.
+func (f *spoolFile) Close() error {
+	err := f.File.Close()
+	if !f.removed {
+		if rerr := os.Remove(f.Name()); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// readerAtFromSeeker adapts an io.ReadSeeker to io.ReaderAt.
+// The lazy loaders issue ReadAt calls concurrently, so each Seek and Read
+// sequence is serialized.
+type readerAtFromSeeker struct {
+	rs io.ReadSeeker
+	mu *sync.Mutex
+}
+
+// ReadAt implements the io.ReaderAt interface for readerAtFromSeeker.
+// It seeks to the specified offset and reads until p is full, returning
+// io.EOF if the input ends first, as required by io.ReaderAt.
+func (r *readerAtFromSeeker) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,147 @@
+package tiff_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/echoflaresat/tiff"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// stripedGray returns an uncompressed grayscale image of w × h pixels in
+// strips of one row, together with its pixels.
+func stripedGray(w, h int) (data, pix []byte) {
+	pix = make([]byte, w*h)
+	var strips [][]byte
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pix[y*w+x] = byte(x + 3*y)
+		}
+		strips = append(strips, pix[y*w:(y+1)*w])
+	}
+	data = tifftest.Build(tifftest.IFD{
+		Entries: tifftest.With(tifftest.Gray(w, h), tifftest.Short(tifftag.RowsPerStrip, 1)),
+		Blocks:  strips,
+	})
+	return data, pix
+}
+
+// readAll reads every pixel of img with ReadRegion on several goroutines and
+// compares them with want.
+func readAll(t *testing.T, img tiff.Image, want []byte) {
+	t.Helper()
+	got := make([]byte, len(want))
+	if err := img.ReadRegion(context.Background(), img.Bounds(), nil, got, 8); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadRegion returned wrong pixels")
+	}
+}
+
+func TestDecodeReadSeeker(t *testing.T) {
+	data, pix := stripedGray(64, 64)
+	// Hide io.ReaderAt so that the seeker adapter is used.
+	r := struct{ io.ReadSeeker }{bytes.NewReader(data)}
+	img, err := tiff.DecodeWithOptions(r, tiff.Options{NoFallback: true, CoalesceGap: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readAll(t, img.(tiff.Image), pix)
+}
+
+func TestDecodeSpooled(t *testing.T) {
+	data, pix := stripedGray(64, 64)
+	for _, tt := range []struct {
+		name      string
+		threshold int64
+	}{
+		{"memory", 1 << 20},
+		{"always memory", -1},
+		{"file", 1024},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			r := struct{ io.Reader }{bytes.NewReader(data)}
+			img, err := tiff.DecodeWithOptions(r, tiff.Options{NoFallback: true, SpoolThreshold: tt.threshold, SpoolDir: dir})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ti := img.(tiff.Image)
+			readAll(t, ti, pix)
+			if err := ti.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("spool directory holds %d files after Close", len(entries))
+			}
+		})
+	}
+}
+
+func TestDecodeSpoolFailure(t *testing.T) {
+	data, _ := stripedGray(64, 64)
+	r := struct{ io.Reader }{bytes.NewReader(data)}
+	missing := t.TempDir() + "/missing"
+	if _, err := tiff.DecodeWithOptions(r, tiff.Options{SpoolThreshold: 1024, SpoolDir: missing}); err == nil {
+		t.Errorf("DecodeWithOptions() succeeded without a spool directory")
+	}
+}
+
+// countingReader counts the bytes read from a plain io.Reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func TestDecodeConfigStream(t *testing.T) {
+	data, _ := stripedGray(64, 64)
+	// The directory is followed by 4 MiB the configuration does not need.
+	data = append(data, make([]byte, 4<<20)...)
+	r := &countingReader{r: bytes.NewReader(data)}
+	cfg, err := tiff.DecodeConfig(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 64 || cfg.Height != 64 {
+		t.Errorf("DecodeConfig() = %dx%d, want 64x64", cfg.Width, cfg.Height)
+	}
+	if r.n >= 1<<20 {
+		t.Errorf("DecodeConfig read %d bytes of a %d byte stream", r.n, len(data))
+	}
+}
+
+func TestDescribeImageStream(t *testing.T) {
+	tests := []struct {
+		name string
+		w, h int
+	}{
+		{"in memory", 64, 64},
+		{"spooled", 8 << 10, 5 << 10}, // the directory follows 40 MiB of pixels
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tifftest.Build(tifftest.IFD{
+				Entries: tifftest.Gray(tt.w, tt.h),
+				Blocks:  [][]byte{make([]byte, tt.w*tt.h)},
+			})
+			d, err := tiff.DescribeImage(struct{ io.Reader }{bytes.NewReader(data)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Width != tt.w || d.Height != tt.h || d.Pages != 1 || !d.Lazy {
+				t.Errorf("DescribeImage() = %+v", d)
+			}
+		})
+	}
+}
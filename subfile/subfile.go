@@ -0,0 +1,54 @@
+// Package subfile defines the TIFF NewSubfileType tag flags, which describe
+// the role of an image file directory within a multi-image TIFF.
+//
+// This corresponds to TIFF tag 254:
+// https://www.awaresystems.be/imaging/tiff/tifftags/newsubfiletype.html
+package subfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the bit field stored in the NewSubfileType tag (tag 254).
+// A zero value denotes a full-resolution primary image.
+type Type uint32
+
+const (
+	// ReducedImage (bit 0) marks a reduced-resolution version of another image,
+	// such as an overview or thumbnail.
+	ReducedImage Type = 1 << 0
+
+	// Page (bit 1) marks a single page of a multi-page image.
+	Page Type = 1 << 1
+
+	// Mask (bit 2) marks a transparency mask for another image in the file.
+	Mask Type = 1 << 2
+)
+
+// Has reports whether all bits of flag are set in t.
+func (t Type) Has(flag Type) bool {
+	return t&flag == flag
+}
+
+// String returns the names of the set flags joined by "|", or "Primary" if
+// no flag is set. Unknown bits are rendered numerically.
+func (t Type) String() string {
+	if t == 0 {
+		return "Primary"
+	}
+	var names []string
+	for _, f := range []struct {
+		flag Type
+		name string
+	}{{ReducedImage, "ReducedImage"}, {Page, "Page"}, {Mask, "Mask"}} {
+		if t.Has(f.flag) {
+			names = append(names, f.name)
+			t &^= f.flag
+		}
+	}
+	if t != 0 {
+		names = append(names, fmt.Sprintf("SubfileType(%#x)", uint32(t)))
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,445 @@
+package tifftag
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+)
+
+// Group identifies the specification that defines a tag.
+type Group uint8
+
+const (
+	// Baseline tags are defined in part 1 of the TIFF 6.0 specification.
+	Baseline Group = iota + 1
+
+	// Extension tags are defined in part 2 of the TIFF 6.0 specification
+	// and its supplements (including TIFF-FX).
+	Extension
+
+	// Private tags are registered by third parties, such as Adobe, IPTC or ICC.
+	Private
+
+	// Exif tags live in the EXIF sub-IFD (plus the pointers to the EXIF and GPS IFDs).
+	Exif
+
+	// GPS tags live in the GPS sub-IFD.
+	GPS
+
+	// GeoTIFF tags describe georeferencing.
+	GeoTIFF
+
+	// DNG tags are defined by the Adobe Digital Negative specification.
+	DNG
+
+	// GDAL tags are the private tags written by the GDAL library.
+	GDAL
+)
+
+// String returns the name of the group.
+func (g Group) String() string {
+	switch g {
+	case Baseline:
+		return "Baseline"
+	case Extension:
+		return "Extension"
+	case Private:
+		return "Private"
+	case Exif:
+		return "Exif"
+	case GPS:
+		return "GPS"
+	case GeoTIFF:
+		return "GeoTIFF"
+	case DNG:
+		return "DNG"
+	case GDAL:
+		return "GDAL"
+	default:
+		return fmt.Sprintf("Group(%d)", g)
+	}
+}
+
+// Special values of Info.Count.
+const (
+	// AnyCount means the number of values is variable or derived from other
+	// tags (for example StripOffsets has one value per strip).
+	AnyCount = -1
+
+	// PerSampleCount means one value is expected per sample (SamplesPerPixel).
+	PerSampleCount = -2
+)
+
+// Info describes the expected shape of a registered tag.
+type Info struct {
+	Tag   Tag
+	Name  string
+	Group Group
+
+	// Types lists the field types the specification allows, preferred first.
+	Types []fieldtype.Type
+
+	// Count is the expected number of values, or AnyCount or PerSampleCount.
+	// For ASCII tags the count includes the terminating NUL.
+	Count int
+
+	// Default holds the value the specification assigns when the tag is
+	// absent, or nil if there is none (or it depends on other tags).
+	// Per-sample defaults hold the value of a single sample.
+	Default []float64
+}
+
+// Accepts reports whether t is one of the field types allowed for the tag.
+func (i Info) Accepts(t fieldtype.Type) bool {
+	for _, allowed := range i.Types {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the registry entry for t.
+// The returned slices are shared and must not be modified.
+func Lookup(t Tag) (Info, bool) {
+	i := sort.Search(len(registry), func(i int) bool { return registry[i].Tag >= t })
+	if i < len(registry) && registry[i].Tag == t {
+		return registry[i], true
+	}
+	return Info{}, false
+}
+
+// LookupName returns the registry entry for the tag with the given name,
+// such as "ImageWidth".
+func LookupName(name string) (Info, bool) {
+	i, ok := byName[name]
+	if !ok {
+		return Info{}, false
+	}
+	return registry[i], true
+}
+
+// All returns the registry entries in ascending tag order.
+func All() []Info {
+	return append([]Info(nil), registry...)
+}
+
+// byName indexes registry by tag name.
+var byName = func() map[string]int {
+	m := make(map[string]int, len(registry))
+	for i, info := range registry {
+		m[info.Name] = i
+	}
+	return m
+}()
+
+// registry holds every known tag, sorted by tag number.
+var registry = []Info{
+	{Tag: GPSVersionID, Name: "GPSVersionID", Group: GPS, Types: []fieldtype.Type{fieldtype.Byte}, Count: 4, Default: []float64{2, 2, 0, 0}},
+	{Tag: GPSLatitudeRef, Name: "GPSLatitudeRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSLatitude, Name: "GPSLatitude", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 3},
+	{Tag: GPSLongitudeRef, Name: "GPSLongitudeRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSLongitude, Name: "GPSLongitude", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 3},
+	{Tag: GPSAltitudeRef, Name: "GPSAltitudeRef", Group: GPS, Types: []fieldtype.Type{fieldtype.Byte}, Count: 1, Default: []float64{0}},
+	{Tag: GPSAltitude, Name: "GPSAltitude", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSTimeStamp, Name: "GPSTimeStamp", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 3},
+	{Tag: GPSSatellites, Name: "GPSSatellites", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: GPSStatus, Name: "GPSStatus", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSMeasureMode, Name: "GPSMeasureMode", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSDOP, Name: "GPSDOP", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSSpeedRef, Name: "GPSSpeedRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSSpeed, Name: "GPSSpeed", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSTrackRef, Name: "GPSTrackRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSTrack, Name: "GPSTrack", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSImgDirectionRef, Name: "GPSImgDirectionRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSImgDirection, Name: "GPSImgDirection", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSMapDatum, Name: "GPSMapDatum", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: GPSDestLatitudeRef, Name: "GPSDestLatitudeRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSDestLatitude, Name: "GPSDestLatitude", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 3},
+	{Tag: GPSDestLongitudeRef, Name: "GPSDestLongitudeRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSDestLongitude, Name: "GPSDestLongitude", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 3},
+	{Tag: GPSDestBearingRef, Name: "GPSDestBearingRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSDestBearing, Name: "GPSDestBearing", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSDestDistanceRef, Name: "GPSDestDistanceRef", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 2},
+	{Tag: GPSDestDistance, Name: "GPSDestDistance", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: GPSProcessingMethod, Name: "GPSProcessingMethod", Group: GPS, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: GPSAreaInformation, Name: "GPSAreaInformation", Group: GPS, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: GPSDateStamp, Name: "GPSDateStamp", Group: GPS, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 11},
+	{Tag: GPSDifferential, Name: "GPSDifferential", Group: GPS, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: GPSHPositioningError, Name: "GPSHPositioningError", Group: GPS, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: NewSubfileType, Name: "NewSubfileType", Group: Baseline, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: SubfileType, Name: "SubfileType", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: ImageWidth, Name: "ImageWidth", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: ImageLength, Name: "ImageLength", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: BitsPerSample, Name: "BitsPerSample", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount, Default: []float64{1}},
+	{Tag: Compression, Name: "Compression", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: PhotometricInterpretation, Name: "PhotometricInterpretation", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: Threshholding, Name: "Threshholding", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: CellWidth, Name: "CellWidth", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: CellLength, Name: "CellLength", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: FillOrder, Name: "FillOrder", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: DocumentName, Name: "DocumentName", Group: Extension, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: ImageDescription, Name: "ImageDescription", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: Make, Name: "Make", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: Model, Name: "Model", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: StripOffsets, Name: "StripOffsets", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Long8}, Count: AnyCount},
+	{Tag: Orientation, Name: "Orientation", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: SamplesPerPixel, Name: "SamplesPerPixel", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: RowsPerStrip, Name: "RowsPerStrip", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1, Default: []float64{4294967295}},
+	{Tag: StripByteCounts, Name: "StripByteCounts", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Long8}, Count: AnyCount},
+	{Tag: MinSampleValue, Name: "MinSampleValue", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount, Default: []float64{0}},
+	{Tag: MaxSampleValue, Name: "MaxSampleValue", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount},
+	{Tag: XResolution, Name: "XResolution", Group: Baseline, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: YResolution, Name: "YResolution", Group: Baseline, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: PlanarConfiguration, Name: "PlanarConfiguration", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: PageName, Name: "PageName", Group: Extension, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: XPosition, Name: "XPosition", Group: Extension, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: YPosition, Name: "YPosition", Group: Extension, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: FreeOffsets, Name: "FreeOffsets", Group: Baseline, Types: []fieldtype.Type{fieldtype.Long}, Count: AnyCount},
+	{Tag: FreeByteCounts, Name: "FreeByteCounts", Group: Baseline, Types: []fieldtype.Type{fieldtype.Long}, Count: AnyCount},
+	{Tag: GrayResponseUnit, Name: "GrayResponseUnit", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{2}},
+	{Tag: GrayResponseCurve, Name: "GrayResponseCurve", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: T4Options, Name: "T4Options", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: T6Options, Name: "T6Options", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: ResolutionUnit, Name: "ResolutionUnit", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{2}},
+	{Tag: PageNumber, Name: "PageNumber", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 2},
+	{Tag: TransferFunction, Name: "TransferFunction", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: Software, Name: "Software", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: DateTime, Name: "DateTime", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 20},
+	{Tag: Artist, Name: "Artist", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: HostComputer, Name: "HostComputer", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: Predictor, Name: "Predictor", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: WhitePoint, Name: "WhitePoint", Group: Extension, Types: []fieldtype.Type{fieldtype.Rational}, Count: 2},
+	{Tag: PrimaryChromaticities, Name: "PrimaryChromaticities", Group: Extension, Types: []fieldtype.Type{fieldtype.Rational}, Count: 6},
+	{Tag: ColorMap, Name: "ColorMap", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: HalftoneHints, Name: "HalftoneHints", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 2},
+	{Tag: TileWidth, Name: "TileWidth", Group: Extension, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: TileLength, Name: "TileLength", Group: Extension, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: TileOffsets, Name: "TileOffsets", Group: Extension, Types: []fieldtype.Type{fieldtype.Long, fieldtype.Long8}, Count: AnyCount},
+	{Tag: TileByteCounts, Name: "TileByteCounts", Group: Extension, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Long8}, Count: AnyCount},
+	{Tag: BadFaxLines, Name: "BadFaxLines", Group: Extension, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: CleanFaxData, Name: "CleanFaxData", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: ConsecutiveBadFaxLines, Name: "ConsecutiveBadFaxLines", Group: Extension, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: SubIFDs, Name: "SubIFDs", Group: Extension, Types: []fieldtype.Type{fieldtype.Long, fieldtype.IFD, fieldtype.Long8, fieldtype.IFD8}, Count: AnyCount},
+	{Tag: InkSet, Name: "InkSet", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: InkNames, Name: "InkNames", Group: Extension, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: NumberOfInks, Name: "NumberOfInks", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{4}},
+	{Tag: DotRange, Name: "DotRange", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Short}, Count: AnyCount},
+	{Tag: TargetPrinter, Name: "TargetPrinter", Group: Extension, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: ExtraSamples, Name: "ExtraSamples", Group: Baseline, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: SampleFormat, Name: "SampleFormat", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount, Default: []float64{1}},
+	{Tag: SMinSampleValue, Name: "SMinSampleValue", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Short, fieldtype.Long, fieldtype.SByte, fieldtype.SShort, fieldtype.SLong, fieldtype.Float, fieldtype.Double}, Count: PerSampleCount},
+	{Tag: SMaxSampleValue, Name: "SMaxSampleValue", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Short, fieldtype.Long, fieldtype.SByte, fieldtype.SShort, fieldtype.SLong, fieldtype.Float, fieldtype.Double}, Count: PerSampleCount},
+	{Tag: TransferRange, Name: "TransferRange", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 6},
+	{Tag: ClipPath, Name: "ClipPath", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte}, Count: AnyCount},
+	{Tag: XClipPathUnits, Name: "XClipPathUnits", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: YClipPathUnits, Name: "YClipPathUnits", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: Indexed, Name: "Indexed", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: JPEGTables, Name: "JPEGTables", Group: Extension, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: OPIProxy, Name: "OPIProxy", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: GlobalParametersIFD, Name: "GlobalParametersIFD", Group: Extension, Types: []fieldtype.Type{fieldtype.Long, fieldtype.IFD}, Count: 1},
+	{Tag: ProfileType, Name: "ProfileType", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: FaxProfile, Name: "FaxProfile", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte}, Count: 1},
+	{Tag: CodingMethods, Name: "CodingMethods", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: VersionYear, Name: "VersionYear", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte}, Count: 4},
+	{Tag: ModeNumber, Name: "ModeNumber", Group: Extension, Types: []fieldtype.Type{fieldtype.Byte}, Count: 1},
+	{Tag: Decode, Name: "Decode", Group: Extension, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: DefaultImageColor, Name: "DefaultImageColor", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: JPEGProc, Name: "JPEGProc", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: JPEGInterchangeFormat, Name: "JPEGInterchangeFormat", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: JPEGInterchangeFormatLength, Name: "JPEGInterchangeFormatLength", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: JPEGRestartInterval, Name: "JPEGRestartInterval", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: JPEGLosslessPredictors, Name: "JPEGLosslessPredictors", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount},
+	{Tag: JPEGPointTransforms, Name: "JPEGPointTransforms", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount},
+	{Tag: JPEGQTables, Name: "JPEGQTables", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: PerSampleCount},
+	{Tag: JPEGDCTables, Name: "JPEGDCTables", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: PerSampleCount},
+	{Tag: JPEGACTables, Name: "JPEGACTables", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: PerSampleCount},
+	{Tag: YCbCrCoefficients, Name: "YCbCrCoefficients", Group: Extension, Types: []fieldtype.Type{fieldtype.Rational}, Count: 3, Default: []float64{0.299, 0.587, 0.114}},
+	{Tag: YCbCrSubSampling, Name: "YCbCrSubSampling", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 2, Default: []float64{2, 2}},
+	{Tag: YCbCrPositioning, Name: "YCbCrPositioning", Group: Extension, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: ReferenceBlackWhite, Name: "ReferenceBlackWhite", Group: Extension, Types: []fieldtype.Type{fieldtype.Rational}, Count: 6},
+	{Tag: StripRowCounts, Name: "StripRowCounts", Group: Extension, Types: []fieldtype.Type{fieldtype.Long}, Count: AnyCount},
+	{Tag: XMP, Name: "XMP", Group: Private, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Undefined}, Count: AnyCount},
+	{Tag: ImageID, Name: "ImageID", Group: Extension, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: Matteing, Name: "Matteing", Group: Private, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: DataType, Name: "DataType", Group: Private, Types: []fieldtype.Type{fieldtype.Short}, Count: PerSampleCount},
+	{Tag: ImageDepth, Name: "ImageDepth", Group: Private, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1, Default: []float64{1}},
+	{Tag: TileDepth, Name: "TileDepth", Group: Private, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1, Default: []float64{1}},
+	{Tag: CFARepeatPatternDim, Name: "CFARepeatPatternDim", Group: Private, Types: []fieldtype.Type{fieldtype.Short}, Count: 2},
+	{Tag: CFAPattern, Name: "CFAPattern", Group: Private, Types: []fieldtype.Type{fieldtype.Byte}, Count: AnyCount},
+	{Tag: Copyright, Name: "Copyright", Group: Baseline, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: ExposureTime, Name: "ExposureTime", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: FNumber, Name: "FNumber", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: ModelPixelScale, Name: "ModelPixelScale", Group: GeoTIFF, Types: []fieldtype.Type{fieldtype.Double}, Count: 3},
+	{Tag: IPTCNAA, Name: "IPTCNAA", Group: Private, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Undefined, fieldtype.Long}, Count: AnyCount},
+	{Tag: ModelTiepoint, Name: "ModelTiepoint", Group: GeoTIFF, Types: []fieldtype.Type{fieldtype.Double}, Count: AnyCount},
+	{Tag: ModelTransformation, Name: "ModelTransformation", Group: GeoTIFF, Types: []fieldtype.Type{fieldtype.Double}, Count: 16},
+	{Tag: Photoshop, Name: "Photoshop", Group: Private, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Undefined}, Count: AnyCount},
+	{Tag: ExifIFD, Name: "ExifIFD", Group: Exif, Types: []fieldtype.Type{fieldtype.Long, fieldtype.IFD}, Count: 1},
+	{Tag: ICCProfile, Name: "ICCProfile", Group: Private, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: ImageLayer, Name: "ImageLayer", Group: Private, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 2},
+	{Tag: GeoKeyDirectory, Name: "GeoKeyDirectory", Group: GeoTIFF, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: GeoDoubleParams, Name: "GeoDoubleParams", Group: GeoTIFF, Types: []fieldtype.Type{fieldtype.Double}, Count: AnyCount},
+	{Tag: GeoASCIIParams, Name: "GeoASCIIParams", Group: GeoTIFF, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: ExposureProgram, Name: "ExposureProgram", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: SpectralSensitivity, Name: "SpectralSensitivity", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: GPSIFD, Name: "GPSIFD", Group: Exif, Types: []fieldtype.Type{fieldtype.Long, fieldtype.IFD}, Count: 1},
+	{Tag: ISOSpeedRatings, Name: "ISOSpeedRatings", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: OECF, Name: "OECF", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: SensitivityType, Name: "SensitivityType", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: StandardOutputSensitivity, Name: "StandardOutputSensitivity", Group: Exif, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: RecommendedExposureIndex, Name: "RecommendedExposureIndex", Group: Exif, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: ISOSpeed, Name: "ISOSpeed", Group: Exif, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: ExifVersion, Name: "ExifVersion", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: 4},
+	{Tag: DateTimeOriginal, Name: "DateTimeOriginal", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 20},
+	{Tag: DateTimeDigitized, Name: "DateTimeDigitized", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 20},
+	{Tag: OffsetTime, Name: "OffsetTime", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 7},
+	{Tag: OffsetTimeOriginal, Name: "OffsetTimeOriginal", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 7},
+	{Tag: OffsetTimeDigitized, Name: "OffsetTimeDigitized", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 7},
+	{Tag: ComponentsConfiguration, Name: "ComponentsConfiguration", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: 4},
+	{Tag: CompressedBitsPerPixel, Name: "CompressedBitsPerPixel", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: ShutterSpeedValue, Name: "ShutterSpeedValue", Group: Exif, Types: []fieldtype.Type{fieldtype.SRational}, Count: 1},
+	{Tag: ApertureValue, Name: "ApertureValue", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: BrightnessValue, Name: "BrightnessValue", Group: Exif, Types: []fieldtype.Type{fieldtype.SRational}, Count: 1},
+	{Tag: ExposureBiasValue, Name: "ExposureBiasValue", Group: Exif, Types: []fieldtype.Type{fieldtype.SRational}, Count: 1},
+	{Tag: MaxApertureValue, Name: "MaxApertureValue", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: SubjectDistance, Name: "SubjectDistance", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: MeteringMode, Name: "MeteringMode", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: LightSource, Name: "LightSource", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: Flash, Name: "Flash", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: FocalLength, Name: "FocalLength", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: SubjectArea, Name: "SubjectArea", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: MakerNote, Name: "MakerNote", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: UserComment, Name: "UserComment", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: SubSecTime, Name: "SubSecTime", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: SubSecTimeOriginal, Name: "SubSecTimeOriginal", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: SubSecTimeDigitized, Name: "SubSecTimeDigitized", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: FlashpixVersion, Name: "FlashpixVersion", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: 4},
+	{Tag: ColorSpace, Name: "ColorSpace", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: PixelXDimension, Name: "PixelXDimension", Group: Exif, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: PixelYDimension, Name: "PixelYDimension", Group: Exif, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1},
+	{Tag: RelatedSoundFile, Name: "RelatedSoundFile", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 13},
+	{Tag: InteroperabilityIFD, Name: "InteroperabilityIFD", Group: Exif, Types: []fieldtype.Type{fieldtype.Long, fieldtype.IFD}, Count: 1},
+	{Tag: FlashEnergy, Name: "FlashEnergy", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: SpatialFrequencyResponse, Name: "SpatialFrequencyResponse", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: FocalPlaneXResolution, Name: "FocalPlaneXResolution", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: FocalPlaneYResolution, Name: "FocalPlaneYResolution", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: FocalPlaneResolutionUnit, Name: "FocalPlaneResolutionUnit", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{2}},
+	{Tag: SubjectLocation, Name: "SubjectLocation", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 2},
+	{Tag: ExposureIndex, Name: "ExposureIndex", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: SensingMethod, Name: "SensingMethod", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: FileSource, Name: "FileSource", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: 1, Default: []float64{3}},
+	{Tag: SceneType, Name: "SceneType", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: 1, Default: []float64{1}},
+	{Tag: CustomRendered, Name: "CustomRendered", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: ExposureMode, Name: "ExposureMode", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: WhiteBalance, Name: "WhiteBalance", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: DigitalZoomRatio, Name: "DigitalZoomRatio", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: FocalLengthIn35mmFilm, Name: "FocalLengthIn35mmFilm", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: SceneCaptureType, Name: "SceneCaptureType", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: GainControl, Name: "GainControl", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: Contrast, Name: "Contrast", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: Saturation, Name: "Saturation", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: Sharpness, Name: "Sharpness", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: DeviceSettingDescription, Name: "DeviceSettingDescription", Group: Exif, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: SubjectDistanceRange, Name: "SubjectDistanceRange", Group: Exif, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: ImageUniqueID, Name: "ImageUniqueID", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: 33},
+	{Tag: CameraOwnerName, Name: "CameraOwnerName", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: BodySerialNumber, Name: "BodySerialNumber", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: LensSpecification, Name: "LensSpecification", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 4},
+	{Tag: LensMake, Name: "LensMake", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: LensModel, Name: "LensModel", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: LensSerialNumber, Name: "LensSerialNumber", Group: Exif, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: GDALMetadata, Name: "GDALMetadata", Group: GDAL, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: GDALNoData, Name: "GDALNoData", Group: GDAL, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: Gamma, Name: "Gamma", Group: Exif, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: LercParameters, Name: "LercParameters", Group: Private, Types: []fieldtype.Type{fieldtype.Long}, Count: AnyCount},
+	{Tag: DNGVersion, Name: "DNGVersion", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: 4},
+	{Tag: DNGBackwardVersion, Name: "DNGBackwardVersion", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: 4},
+	{Tag: UniqueCameraModel, Name: "UniqueCameraModel", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: LocalizedCameraModel, Name: "LocalizedCameraModel", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: CFAPlaneColor, Name: "CFAPlaneColor", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: AnyCount, Default: []float64{0, 1, 2}},
+	{Tag: CFALayout, Name: "CFALayout", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{1}},
+	{Tag: LinearizationTable, Name: "LinearizationTable", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: AnyCount},
+	{Tag: BlackLevelRepeatDim, Name: "BlackLevelRepeatDim", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: 2, Default: []float64{1, 1}},
+	{Tag: BlackLevel, Name: "BlackLevel", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Rational}, Count: AnyCount, Default: []float64{0}},
+	{Tag: BlackLevelDeltaH, Name: "BlackLevelDeltaH", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: BlackLevelDeltaV, Name: "BlackLevelDeltaV", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: WhiteLevel, Name: "WhiteLevel", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: PerSampleCount},
+	{Tag: DefaultScale, Name: "DefaultScale", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 2, Default: []float64{1, 1}},
+	{Tag: DefaultCropOrigin, Name: "DefaultCropOrigin", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Rational}, Count: 2, Default: []float64{0, 0}},
+	{Tag: DefaultCropSize, Name: "DefaultCropSize", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Rational}, Count: 2},
+	{Tag: ColorMatrix1, Name: "ColorMatrix1", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: ColorMatrix2, Name: "ColorMatrix2", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: CameraCalibration1, Name: "CameraCalibration1", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: CameraCalibration2, Name: "CameraCalibration2", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: ReductionMatrix1, Name: "ReductionMatrix1", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: ReductionMatrix2, Name: "ReductionMatrix2", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: AnalogBalance, Name: "AnalogBalance", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: AnyCount},
+	{Tag: AsShotNeutral, Name: "AsShotNeutral", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Rational}, Count: AnyCount},
+	{Tag: AsShotWhiteXY, Name: "AsShotWhiteXY", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 2},
+	{Tag: BaselineExposure, Name: "BaselineExposure", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: 1, Default: []float64{0}},
+	{Tag: BaselineNoise, Name: "BaselineNoise", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1, Default: []float64{1}},
+	{Tag: BaselineSharpness, Name: "BaselineSharpness", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1, Default: []float64{1}},
+	{Tag: BayerGreenSplit, Name: "BayerGreenSplit", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: LinearResponseLimit, Name: "LinearResponseLimit", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1, Default: []float64{1}},
+	{Tag: CameraSerialNumber, Name: "CameraSerialNumber", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: LensInfo, Name: "LensInfo", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 4},
+	{Tag: ChromaBlurRadius, Name: "ChromaBlurRadius", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: AntiAliasStrength, Name: "AntiAliasStrength", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1, Default: []float64{1}},
+	{Tag: ShadowScale, Name: "ShadowScale", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1, Default: []float64{1}},
+	{Tag: DNGPrivateData, Name: "DNGPrivateData", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: AnyCount},
+	{Tag: MakerNoteSafety, Name: "MakerNoteSafety", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: CalibrationIlluminant1, Name: "CalibrationIlluminant1", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: CalibrationIlluminant2, Name: "CalibrationIlluminant2", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: 1},
+	{Tag: BestQualityScale, Name: "BestQualityScale", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1, Default: []float64{1}},
+	{Tag: RawDataUniqueID, Name: "RawDataUniqueID", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: 16},
+	{Tag: OriginalRawFileName, Name: "OriginalRawFileName", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: OriginalRawFileData, Name: "OriginalRawFileData", Group: DNG, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: ActiveArea, Name: "ActiveArea", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 4},
+	{Tag: MaskedAreas, Name: "MaskedAreas", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: AnyCount},
+	{Tag: AsShotICCProfile, Name: "AsShotICCProfile", Group: DNG, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: AsShotPreProfileMatrix, Name: "AsShotPreProfileMatrix", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: CurrentICCProfile, Name: "CurrentICCProfile", Group: DNG, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: CurrentPreProfileMatrix, Name: "CurrentPreProfileMatrix", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: ColorimetricReference, Name: "ColorimetricReference", Group: DNG, Types: []fieldtype.Type{fieldtype.Short}, Count: 1, Default: []float64{0}},
+	{Tag: CameraCalibrationSignature, Name: "CameraCalibrationSignature", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: ProfileCalibrationSignature, Name: "ProfileCalibrationSignature", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: AsShotProfileName, Name: "AsShotProfileName", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: NoiseReductionApplied, Name: "NoiseReductionApplied", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 1},
+	{Tag: ProfileName, Name: "ProfileName", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: ProfileHueSatMapDims, Name: "ProfileHueSatMapDims", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 3},
+	{Tag: ProfileHueSatMapData1, Name: "ProfileHueSatMapData1", Group: DNG, Types: []fieldtype.Type{fieldtype.Float}, Count: AnyCount},
+	{Tag: ProfileHueSatMapData2, Name: "ProfileHueSatMapData2", Group: DNG, Types: []fieldtype.Type{fieldtype.Float}, Count: AnyCount},
+	{Tag: ProfileToneCurve, Name: "ProfileToneCurve", Group: DNG, Types: []fieldtype.Type{fieldtype.Float}, Count: AnyCount},
+	{Tag: ProfileEmbedPolicy, Name: "ProfileEmbedPolicy", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: ProfileCopyright, Name: "ProfileCopyright", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: ForwardMatrix1, Name: "ForwardMatrix1", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: ForwardMatrix2, Name: "ForwardMatrix2", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: AnyCount},
+	{Tag: PreviewApplicationName, Name: "PreviewApplicationName", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: PreviewApplicationVersion, Name: "PreviewApplicationVersion", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: PreviewSettingsName, Name: "PreviewSettingsName", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII, fieldtype.Byte}, Count: AnyCount},
+	{Tag: PreviewSettingsDigest, Name: "PreviewSettingsDigest", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: 16},
+	{Tag: PreviewColorSpace, Name: "PreviewColorSpace", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 1},
+	{Tag: PreviewDateTime, Name: "PreviewDateTime", Group: DNG, Types: []fieldtype.Type{fieldtype.ASCII}, Count: AnyCount},
+	{Tag: RawImageDigest, Name: "RawImageDigest", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Undefined}, Count: 16},
+	{Tag: OriginalRawFileDigest, Name: "OriginalRawFileDigest", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte, fieldtype.Undefined}, Count: 16},
+	{Tag: SubTileBlockSize, Name: "SubTileBlockSize", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 2, Default: []float64{1, 1}},
+	{Tag: RowInterleaveFactor, Name: "RowInterleaveFactor", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 1, Default: []float64{1}},
+	{Tag: ProfileLookTableDims, Name: "ProfileLookTableDims", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 3},
+	{Tag: ProfileLookTableData, Name: "ProfileLookTableData", Group: DNG, Types: []fieldtype.Type{fieldtype.Float}, Count: AnyCount},
+	{Tag: OpcodeList1, Name: "OpcodeList1", Group: DNG, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: OpcodeList2, Name: "OpcodeList2", Group: DNG, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: OpcodeList3, Name: "OpcodeList3", Group: DNG, Types: []fieldtype.Type{fieldtype.Undefined}, Count: AnyCount},
+	{Tag: NoiseProfile, Name: "NoiseProfile", Group: DNG, Types: []fieldtype.Type{fieldtype.Double}, Count: AnyCount},
+	{Tag: OriginalDefaultFinalSize, Name: "OriginalDefaultFinalSize", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 2},
+	{Tag: OriginalBestQualityFinalSize, Name: "OriginalBestQualityFinalSize", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long}, Count: 2},
+	{Tag: OriginalDefaultCropSize, Name: "OriginalDefaultCropSize", Group: DNG, Types: []fieldtype.Type{fieldtype.Short, fieldtype.Long, fieldtype.Rational}, Count: 2},
+	{Tag: ProfileHueSatMapEncoding, Name: "ProfileHueSatMapEncoding", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: ProfileLookTableEncoding, Name: "ProfileLookTableEncoding", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: BaselineExposureOffset, Name: "BaselineExposureOffset", Group: DNG, Types: []fieldtype.Type{fieldtype.SRational}, Count: 1, Default: []float64{0}},
+	{Tag: DefaultBlackRender, Name: "DefaultBlackRender", Group: DNG, Types: []fieldtype.Type{fieldtype.Long}, Count: 1, Default: []float64{0}},
+	{Tag: NewRawImageDigest, Name: "NewRawImageDigest", Group: DNG, Types: []fieldtype.Type{fieldtype.Byte}, Count: 16},
+	{Tag: RawToPreviewGain, Name: "RawToPreviewGain", Group: DNG, Types: []fieldtype.Type{fieldtype.Double}, Count: 1, Default: []float64{1}},
+	{Tag: DefaultUserCrop, Name: "DefaultUserCrop", Group: DNG, Types: []fieldtype.Type{fieldtype.Rational}, Count: 4, Default: []float64{0, 0, 1, 1}},
+}
@@ -0,0 +1,96 @@
+package tifftag
+
+import (
+	"testing"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+)
+
+func TestRegistryConsistent(t *testing.T) {
+	names := map[string]Tag{}
+	for i, info := range registry {
+		if i > 0 && info.Tag <= registry[i-1].Tag {
+			t.Errorf("%s (%d) not sorted after %s (%d)", info.Name, info.Tag, registry[i-1].Name, registry[i-1].Tag)
+		}
+		if prev, ok := names[info.Name]; ok {
+			t.Errorf("name %s used by tags %d and %d", info.Name, prev, info.Tag)
+		}
+		names[info.Name] = info.Tag
+		if info.Name == "" || len(info.Types) == 0 || info.Group == 0 {
+			t.Errorf("incomplete entry %+v", info)
+		}
+		if info.Count == 0 || info.Count < PerSampleCount {
+			t.Errorf("%s: invalid count %d", info.Name, info.Count)
+		}
+		if info.Default != nil && info.Count > 0 && len(info.Default) != info.Count {
+			t.Errorf("%s: %d default values for count %d", info.Name, len(info.Default), info.Count)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, tt := range []struct {
+		tag     Tag
+		name    string
+		group   Group
+		count   int
+		def     []float64
+		accepts fieldtype.Type
+	}{
+		{ImageWidth, "ImageWidth", Baseline, 1, nil, fieldtype.Long},
+		{Compression, "Compression", Baseline, 1, []float64{1}, fieldtype.Short},
+		{BitsPerSample, "BitsPerSample", Baseline, PerSampleCount, []float64{1}, fieldtype.Short},
+		{PlanarConfiguration, "PlanarConfiguration", Baseline, 1, []float64{1}, fieldtype.Short},
+		{StripOffsets, "StripOffsets", Baseline, AnyCount, nil, fieldtype.Long8},
+		{ModelTiepoint, "ModelTiepoint", GeoTIFF, AnyCount, nil, fieldtype.Double},
+		{GPSLatitude, "GPSLatitude", GPS, 3, nil, fieldtype.Rational},
+	} {
+		info, ok := Lookup(tt.tag)
+		if !ok {
+			t.Errorf("Lookup(%d) not found", tt.tag)
+			continue
+		}
+		if info.Name != tt.name || info.Group != tt.group || info.Count != tt.count {
+			t.Errorf("Lookup(%d) = %+v", tt.tag, info)
+		}
+		if len(info.Default) != len(tt.def) || (len(tt.def) > 0 && info.Default[0] != tt.def[0]) {
+			t.Errorf("%s: Default = %v, want %v", tt.name, info.Default, tt.def)
+		}
+		if !info.Accepts(tt.accepts) {
+			t.Errorf("%s does not accept %s", tt.name, tt.accepts)
+		}
+		if byName, ok := LookupName(tt.name); !ok || byName.Tag != tt.tag {
+			t.Errorf("LookupName(%s) = %v, %v", tt.name, byName.Tag, ok)
+		}
+		if s := tt.tag.String(); s != tt.name {
+			t.Errorf("String() = %q, want %q", s, tt.name)
+		}
+	}
+
+	if info, _ := Lookup(ImageWidth); info.Accepts(fieldtype.ASCII) {
+		t.Errorf("ImageWidth accepts ASCII")
+	}
+	if _, ok := Lookup(65000); ok {
+		t.Errorf("Lookup(65000) found a private tag")
+	}
+	if _, ok := LookupName("NoSuchTag"); ok {
+		t.Errorf("LookupName(NoSuchTag) found")
+	}
+	if s := Tag(65000).String(); s != "Tag(65000)" {
+		t.Errorf("String() = %q", s)
+	}
+	if s := Group(99).String(); s != "Group(99)" {
+		t.Errorf("Group.String() = %q", s)
+	}
+}
+
+func TestAllReturnsCopy(t *testing.T) {
+	all := All()
+	if len(all) != len(registry) {
+		t.Fatalf("All() returned %d entries, want %d", len(all), len(registry))
+	}
+	all[0].Name = "changed"
+	if registry[0].Name == "changed" {
+		t.Errorf("All() exposes the registry")
+	}
+}
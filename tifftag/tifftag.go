@@ -1,6 +1,10 @@
 // Package tifftag defines known TIFF tag identifiers used in image metadata.
 // These tag constants correspond to the TIFF 6.0 specification and supplements,
-// including common fields such as ImageWidth, Compression, and TileOffsets.
+// including common fields such as ImageWidth, Compression, and TileOffsets,
+// as well as the EXIF, GPS, GeoTIFF, DNG and GDAL tag sets.
+//
+// Lookup returns each tag's name, allowed field types, expected count and
+// default value.
 //
 // For reference, see:
 // https://www.loc.gov/preservation/digital/formats/content/tiff_tags.shtml
@@ -13,6 +17,108 @@ import "fmt"
 type Tag uint16
 
 const (
+	// GPSVersionID is the version of the GPS IFD (GPS IFD).
+	GPSVersionID Tag = 0
+
+	// GPSLatitudeRef is "N" or "S" (GPS IFD).
+	GPSLatitudeRef Tag = 1
+
+	// GPSLatitude is the latitude as degrees, minutes and seconds (GPS IFD).
+	GPSLatitude Tag = 2
+
+	// GPSLongitudeRef is "E" or "W" (GPS IFD).
+	GPSLongitudeRef Tag = 3
+
+	// GPSLongitude is the longitude as degrees, minutes and seconds (GPS IFD).
+	GPSLongitude Tag = 4
+
+	// GPSAltitudeRef is 0 above and 1 below sea level (GPS IFD).
+	GPSAltitudeRef Tag = 5
+
+	// GPSAltitude is the altitude in meters (GPS IFD).
+	GPSAltitude Tag = 6
+
+	// GPSTimeStamp is the UTC time as hours, minutes and seconds (GPS IFD).
+	GPSTimeStamp Tag = 7
+
+	// GPSSatellites describes the satellites used for measurement (GPS IFD).
+	GPSSatellites Tag = 8
+
+	// GPSStatus is the receiver status (GPS IFD).
+	GPSStatus Tag = 9
+
+	// GPSMeasureMode is the 2D or 3D measurement mode (GPS IFD).
+	GPSMeasureMode Tag = 10
+
+	// GPSDOP is the dilution of precision (GPS IFD).
+	GPSDOP Tag = 11
+
+	// GPSSpeedRef is the unit of GPSSpeed (GPS IFD).
+	GPSSpeedRef Tag = 12
+
+	// GPSSpeed is the speed of the receiver (GPS IFD).
+	GPSSpeed Tag = 13
+
+	// GPSTrackRef is the reference of GPSTrack (GPS IFD).
+	GPSTrackRef Tag = 14
+
+	// GPSTrack is the direction of movement (GPS IFD).
+	GPSTrack Tag = 15
+
+	// GPSImgDirectionRef is the reference of GPSImgDirection (GPS IFD).
+	GPSImgDirectionRef Tag = 16
+
+	// GPSImgDirection is the direction of the image when captured (GPS IFD).
+	GPSImgDirection Tag = 17
+
+	// GPSMapDatum is the geodetic survey data used (GPS IFD).
+	GPSMapDatum Tag = 18
+
+	// GPSDestLatitudeRef is "N" or "S" for the destination point (GPS IFD).
+	GPSDestLatitudeRef Tag = 19
+
+	// GPSDestLatitude is the latitude of the destination point (GPS IFD).
+	GPSDestLatitude Tag = 20
+
+	// GPSDestLongitudeRef is "E" or "W" for the destination point (GPS IFD).
+	GPSDestLongitudeRef Tag = 21
+
+	// GPSDestLongitude is the longitude of the destination point (GPS IFD).
+	GPSDestLongitude Tag = 22
+
+	// GPSDestBearingRef is "T" (true) or "M" (magnetic) for GPSDestBearing (GPS IFD).
+	GPSDestBearingRef Tag = 23
+
+	// GPSDestBearing is the bearing to the destination point in degrees (GPS IFD).
+	GPSDestBearing Tag = 24
+
+	// GPSDestDistanceRef is the unit of GPSDestDistance: "K", "M" or "N" (GPS IFD).
+	GPSDestDistanceRef Tag = 25
+
+	// GPSDestDistance is the distance to the destination point (GPS IFD).
+	GPSDestDistance Tag = 26
+
+	// GPSProcessingMethod names the method used for location finding (GPS IFD).
+	GPSProcessingMethod Tag = 27
+
+	// GPSAreaInformation names the GPS area (GPS IFD).
+	GPSAreaInformation Tag = 28
+
+	// GPSDateStamp is the UTC date as "YYYY:MM:DD" (GPS IFD).
+	GPSDateStamp Tag = 29
+
+	// GPSDifferential indicates whether differential correction was applied (GPS IFD).
+	GPSDifferential Tag = 30
+
+	// GPSHPositioningError is the horizontal positioning error in meters (GPS IFD).
+	GPSHPositioningError Tag = 31
+
+	// NewSubfileType flags reduced-resolution images, pages and transparency masks.
+	NewSubfileType Tag = 254
+
+	// SubfileType is the deprecated predecessor of NewSubfileType.
+	SubfileType Tag = 255
+
 	// ImageWidth specifies the number of columns (pixels) in the image.
 	ImageWidth Tag = 256
 
@@ -28,9 +134,36 @@ const (
 	// PhotometricInterpretation defines how pixel values should be interpreted.
 	PhotometricInterpretation Tag = 262
 
+	// Threshholding describes the dithering applied to bilevel images (sic).
+	Threshholding Tag = 263
+
+	// CellWidth is the width of the dithering matrix.
+	CellWidth Tag = 264
+
+	// CellLength is the height of the dithering matrix.
+	CellLength Tag = 265
+
+	// FillOrder is the logical order of bits within a byte.
+	FillOrder Tag = 266
+
+	// DocumentName is the name of the document the image was scanned from.
+	DocumentName Tag = 269
+
+	// ImageDescription describes the subject of the image.
+	ImageDescription Tag = 270
+
+	// Make is the manufacturer of the scanner or camera.
+	Make Tag = 271
+
+	// Model is the model name of the scanner or camera.
+	Model Tag = 272
+
 	// StripOffsets contains the offsets to image data strips.
 	StripOffsets Tag = 273
 
+	// Orientation specifies how stored rows and columns map to the displayed image.
+	Orientation Tag = 274
+
 	// SamplesPerPixel defines the number of components per pixel.
 	SamplesPerPixel Tag = 277
 
@@ -40,9 +173,84 @@ const (
 	// StripByteCounts contains the byte size of each strip.
 	StripByteCounts Tag = 279
 
+	// MinSampleValue is the minimum component value used.
+	MinSampleValue Tag = 280
+
+	// MaxSampleValue is the maximum component value used; it defaults to 2**BitsPerSample-1.
+	MaxSampleValue Tag = 281
+
+	// XResolution is the number of pixels per ResolutionUnit in the image width direction.
+	XResolution Tag = 282
+
+	// YResolution is the number of pixels per ResolutionUnit in the image length direction.
+	YResolution Tag = 283
+
 	// PlanarConfiguration specifies whether components are stored together or separately.
 	PlanarConfiguration Tag = 284
 
+	// PageName is the name of the page the image was scanned from.
+	PageName Tag = 285
+
+	// XPosition is the horizontal offset of the image in ResolutionUnits.
+	XPosition Tag = 286
+
+	// YPosition is the vertical offset of the image in ResolutionUnits.
+	YPosition Tag = 287
+
+	// FreeOffsets lists the byte offsets of unused string data.
+	FreeOffsets Tag = 288
+
+	// FreeByteCounts lists the sizes of unused string data.
+	FreeByteCounts Tag = 289
+
+	// GrayResponseUnit is the precision of GrayResponseCurve.
+	GrayResponseUnit Tag = 290
+
+	// GrayResponseCurve holds the optical density of each gray level.
+	GrayResponseCurve Tag = 291
+
+	// T4Options holds CCITT Group 3 encoding options.
+	T4Options Tag = 292
+
+	// T6Options holds CCITT Group 4 encoding options.
+	T6Options Tag = 293
+
+	// ResolutionUnit is the unit of XResolution and YResolution.
+	ResolutionUnit Tag = 296
+
+	// PageNumber holds the page number and the total number of pages.
+	PageNumber Tag = 297
+
+	// TransferFunction describes the transfer function of the image in tabular form.
+	TransferFunction Tag = 301
+
+	// Software names the software that created the image.
+	Software Tag = 305
+
+	// DateTime is the creation date and time of the image.
+	DateTime Tag = 306
+
+	// Artist is the person who created the image.
+	Artist Tag = 315
+
+	// HostComputer is the computer used to create the image.
+	HostComputer Tag = 316
+
+	// Predictor is the mathematical operator applied before compression.
+	Predictor Tag = 317
+
+	// WhitePoint is the chromaticity of the white point.
+	WhitePoint Tag = 318
+
+	// PrimaryChromaticities holds the chromaticities of the primaries.
+	PrimaryChromaticities Tag = 319
+
+	// ColorMap is the palette of a palette-color image.
+	ColorMap Tag = 320
+
+	// HalftoneHints conveys the highlight and shadow values for halftoning.
+	HalftoneHints Tag = 321
+
 	// TileWidth defines the width of a tile in pixels.
 	TileWidth Tag = 322
 
@@ -54,41 +262,697 @@ const (
 
 	// TileByteCounts contains the byte size of each tile.
 	TileByteCounts Tag = 325
+
+	// BadFaxLines is the number of scan lines with an incorrect pixel count.
+	BadFaxLines Tag = 326
+
+	// CleanFaxData tells whether bad fax lines were regenerated.
+	CleanFaxData Tag = 327
+
+	// ConsecutiveBadFaxLines is the longest run of consecutive bad fax lines.
+	ConsecutiveBadFaxLines Tag = 328
+
+	// SubIFDs lists the offsets of child IFDs.
+	SubIFDs Tag = 330
+
+	// InkSet is the set of inks used in a separated image.
+	InkSet Tag = 332
+
+	// InkNames holds the names of the inks used in a separated image.
+	InkNames Tag = 333
+
+	// NumberOfInks is the number of inks in a separated image.
+	NumberOfInks Tag = 334
+
+	// DotRange holds the component values for 0% and 100% dot.
+	DotRange Tag = 336
+
+	// TargetPrinter describes the intended printing environment.
+	TargetPrinter Tag = 337
+
+	// ExtraSamples describes the meaning of extra components, such as alpha.
+	ExtraSamples Tag = 338
+
+	// SampleFormat specifies how to interpret each data sample.
+	SampleFormat Tag = 339
+
+	// SMinSampleValue is the minimum sample value, in the SampleFormat type.
+	SMinSampleValue Tag = 340
+
+	// SMaxSampleValue is the maximum sample value, in the SampleFormat type.
+	SMaxSampleValue Tag = 341
+
+	// TransferRange expands the range of TransferFunction.
+	TransferRange Tag = 342
+
+	// ClipPath is a clipping path outlining the image.
+	ClipPath Tag = 343
+
+	// XClipPathUnits is the number of horizontal ClipPath units.
+	XClipPathUnits Tag = 344
+
+	// YClipPathUnits is the number of vertical ClipPath units.
+	YClipPathUnits Tag = 345
+
+	// Indexed tells whether the image is an indexed-color image.
+	Indexed Tag = 346
+
+	// JPEGTables holds the JPEG quantization and Huffman tables shared by all strips or tiles.
+	JPEGTables Tag = 347
+
+	// OPIProxy tells whether the image is a low-resolution proxy of a high-resolution image.
+	OPIProxy Tag = 351
+
+	// GlobalParametersIFD is the offset to the TIFF-FX global parameters IFD.
+	GlobalParametersIFD Tag = 400
+
+	// ProfileType is the TIFF-FX profile type.
+	ProfileType Tag = 401
+
+	// FaxProfile is the TIFF-FX fax profile.
+	FaxProfile Tag = 402
+
+	// CodingMethods lists the TIFF-FX compression methods used.
+	CodingMethods Tag = 403
+
+	// VersionYear is the year of the TIFF-FX standard.
+	VersionYear Tag = 404
+
+	// ModeNumber is the TIFF-FX mode.
+	ModeNumber Tag = 405
+
+	// Decode maps sample values to the color space of TIFF-FX images.
+	Decode Tag = 433
+
+	// DefaultImageColor is the background color of TIFF-FX images.
+	DefaultImageColor Tag = 434
+
+	// JPEGProc is the old-style JPEG process.
+	JPEGProc Tag = 512
+
+	// JPEGInterchangeFormat is the offset of an old-style JPEG SOI marker.
+	JPEGInterchangeFormat Tag = 513
+
+	// JPEGInterchangeFormatLength is the length of the old-style JPEG stream.
+	JPEGInterchangeFormatLength Tag = 514
+
+	// JPEGRestartInterval is the old-style JPEG restart interval.
+	JPEGRestartInterval Tag = 515
+
+	// JPEGLosslessPredictors lists the old-style JPEG lossless predictors.
+	JPEGLosslessPredictors Tag = 517
+
+	// JPEGPointTransforms lists the old-style JPEG point transforms.
+	JPEGPointTransforms Tag = 518
+
+	// JPEGQTables lists the offsets of the old-style JPEG quantization tables.
+	JPEGQTables Tag = 519
+
+	// JPEGDCTables lists the offsets of the old-style JPEG DC Huffman tables.
+	JPEGDCTables Tag = 520
+
+	// JPEGACTables lists the offsets of the old-style JPEG AC Huffman tables.
+	JPEGACTables Tag = 521
+
+	// YCbCrCoefficients are the coefficients for the RGB to YCbCr transform.
+	YCbCrCoefficients Tag = 529
+
+	// YCbCrSubSampling is the chroma subsampling factor.
+	YCbCrSubSampling Tag = 530
+
+	// YCbCrPositioning is the position of chroma samples relative to luma.
+	YCbCrPositioning Tag = 531
+
+	// ReferenceBlackWhite holds the headroom and footroom values for each component.
+	ReferenceBlackWhite Tag = 532
+
+	// StripRowCounts holds the number of rows in each strip of a TIFF-FX image.
+	StripRowCounts Tag = 559
+
+	// XMP holds an embedded XMP packet (XML).
+	XMP Tag = 700
+
+	// ImageID is the OPI name of the full-resolution image.
+	ImageID Tag = 32781
+
+	// Matteing is the obsolete predecessor of ExtraSamples.
+	Matteing Tag = 32995
+
+	// DataType is the obsolete predecessor of SampleFormat.
+	DataType Tag = 32996
+
+	// ImageDepth is the number of z slices in a volumetric image.
+	ImageDepth Tag = 32997
+
+	// TileDepth is the number of z slices in a volumetric tile.
+	TileDepth Tag = 32998
+
+	// CFARepeatPatternDim is the size of the color filter array pattern (TIFF/EP).
+	CFARepeatPatternDim Tag = 33421
+
+	// CFAPattern is the color filter array pattern (TIFF/EP).
+	CFAPattern Tag = 33422
+
+	// Copyright is the copyright notice of the image.
+	Copyright Tag = 33432
+
+	// ExposureTime is the exposure time in seconds (EXIF IFD).
+	ExposureTime Tag = 33434
+
+	// FNumber is the F number (EXIF IFD).
+	FNumber Tag = 33437
+
+	// ModelPixelScale holds the GeoTIFF raster-to-model scale (ScaleX, ScaleY, ScaleZ).
+	ModelPixelScale Tag = 33550
+
+	// IPTCNAA holds an IPTC/NAA information interchange model block.
+	IPTCNAA Tag = 33723
+
+	// ModelTiepoint holds GeoTIFF raster-to-model tie points (I, J, K, X, Y, Z).
+	ModelTiepoint Tag = 33922
+
+	// ModelTransformation holds the GeoTIFF 4x4 raster-to-model transformation matrix.
+	ModelTransformation Tag = 34264
+
+	// Photoshop holds Photoshop image resource blocks.
+	Photoshop Tag = 34377
+
+	// ExifIFD is the offset of the EXIF IFD.
+	ExifIFD Tag = 34665
+
+	// ICCProfile holds an embedded ICC color profile.
+	ICCProfile Tag = 34675
+
+	// ImageLayer identifies the Mixed Raster Content layer of the image.
+	ImageLayer Tag = 34732
+
+	// GeoKeyDirectory holds the GeoTIFF GeoKey directory.
+	GeoKeyDirectory Tag = 34735
+
+	// GeoDoubleParams holds the DOUBLE values referenced by the GeoKey directory.
+	GeoDoubleParams Tag = 34736
+
+	// GeoASCIIParams holds the ASCII values referenced by the GeoKey directory.
+	GeoASCIIParams Tag = 34737
+
+	// ExposureProgram is the program used to set the exposure (EXIF IFD).
+	ExposureProgram Tag = 34850
+
+	// SpectralSensitivity describes the spectral sensitivity of each channel (EXIF).
+	SpectralSensitivity Tag = 34852
+
+	// GPSIFD is the offset of the GPS IFD.
+	GPSIFD Tag = 34853
+
+	// ISOSpeedRatings is the ISO sensitivity (PhotographicSensitivity, EXIF IFD).
+	ISOSpeedRatings Tag = 34855
+
+	// OECF is the opto-electronic conversion function (EXIF).
+	OECF Tag = 34856
+
+	// SensitivityType tells which sensitivity parameter ISOSpeedRatings holds (EXIF).
+	SensitivityType Tag = 34864
+
+	// StandardOutputSensitivity is the standard output sensitivity (EXIF).
+	StandardOutputSensitivity Tag = 34865
+
+	// RecommendedExposureIndex is the recommended exposure index (EXIF).
+	RecommendedExposureIndex Tag = 34866
+
+	// ISOSpeed is the ISO speed (EXIF).
+	ISOSpeed Tag = 34867
+
+	// ExifVersion is the version of the EXIF standard (EXIF IFD).
+	ExifVersion Tag = 36864
+
+	// DateTimeOriginal is the date and time the image was captured (EXIF IFD).
+	DateTimeOriginal Tag = 36867
+
+	// DateTimeDigitized is the date and time the image was digitized (EXIF IFD).
+	DateTimeDigitized Tag = 36868
+
+	// OffsetTime is the UTC offset of DateTime (EXIF IFD).
+	OffsetTime Tag = 36880
+
+	// OffsetTimeOriginal is the UTC offset of DateTimeOriginal (EXIF IFD).
+	OffsetTimeOriginal Tag = 36881
+
+	// OffsetTimeDigitized is the UTC offset of DateTimeDigitized (EXIF IFD).
+	OffsetTimeDigitized Tag = 36882
+
+	// ComponentsConfiguration is the order of the compressed components (EXIF).
+	ComponentsConfiguration Tag = 37121
+
+	// CompressedBitsPerPixel is the compressed bits per pixel (EXIF).
+	CompressedBitsPerPixel Tag = 37122
+
+	// ShutterSpeedValue is the shutter speed in APEX units (EXIF).
+	ShutterSpeedValue Tag = 37377
+
+	// ApertureValue is the lens aperture in APEX units (EXIF).
+	ApertureValue Tag = 37378
+
+	// BrightnessValue is the brightness in APEX units (EXIF).
+	BrightnessValue Tag = 37379
+
+	// ExposureBiasValue is the exposure bias in EV (EXIF IFD).
+	ExposureBiasValue Tag = 37380
+
+	// MaxApertureValue is the smallest F number of the lens in APEX units (EXIF).
+	MaxApertureValue Tag = 37381
+
+	// SubjectDistance is the distance to the subject in meters (EXIF).
+	SubjectDistance Tag = 37382
+
+	// MeteringMode is the metering mode (EXIF IFD).
+	MeteringMode Tag = 37383
+
+	// LightSource is the kind of light source (EXIF).
+	LightSource Tag = 37384
+
+	// Flash describes the flash status (EXIF IFD).
+	Flash Tag = 37385
+
+	// FocalLength is the focal length of the lens in millimeters (EXIF IFD).
+	FocalLength Tag = 37386
+
+	// SubjectArea is the location and area of the main subject (EXIF).
+	SubjectArea Tag = 37396
+
+	// MakerNote holds manufacturer-specific data (EXIF).
+	MakerNote Tag = 37500
+
+	// UserComment holds user comments (EXIF).
+	UserComment Tag = 37510
+
+	// SubSecTime holds fractions of seconds for DateTime (EXIF).
+	SubSecTime Tag = 37520
+
+	// SubSecTimeOriginal holds fractions of a second of DateTimeOriginal (EXIF IFD).
+	SubSecTimeOriginal Tag = 37521
+
+	// SubSecTimeDigitized holds fractions of seconds for DateTimeDigitized (EXIF).
+	SubSecTimeDigitized Tag = 37522
+
+	// FlashpixVersion is the supported Flashpix format version (EXIF).
+	FlashpixVersion Tag = 40960
+
+	// ColorSpace is the color space information tag (EXIF).
+	ColorSpace Tag = 40961
+
+	// PixelXDimension is the valid width of the compressed image (EXIF).
+	PixelXDimension Tag = 40962
+
+	// PixelYDimension is the valid height of the compressed image (EXIF).
+	PixelYDimension Tag = 40963
+
+	// RelatedSoundFile names an audio file related to the image (EXIF).
+	RelatedSoundFile Tag = 40964
+
+	// InteroperabilityIFD is the offset to the Interoperability IFD (EXIF).
+	InteroperabilityIFD Tag = 40965
+
+	// FlashEnergy is the strobe energy in BCPS (EXIF).
+	FlashEnergy Tag = 41483
+
+	// SpatialFrequencyResponse is the spatial frequency table (EXIF).
+	SpatialFrequencyResponse Tag = 41484
+
+	// FocalPlaneXResolution is the horizontal pixel density on the focal plane (EXIF).
+	FocalPlaneXResolution Tag = 41486
+
+	// FocalPlaneYResolution is the vertical pixel density on the focal plane (EXIF).
+	FocalPlaneYResolution Tag = 41487
+
+	// FocalPlaneResolutionUnit is the unit of the focal plane resolution (EXIF).
+	FocalPlaneResolutionUnit Tag = 41488
+
+	// SubjectLocation is the location of the main subject (EXIF).
+	SubjectLocation Tag = 41492
+
+	// ExposureIndex is the selected exposure index (EXIF).
+	ExposureIndex Tag = 41493
+
+	// SensingMethod is the image sensor type (EXIF).
+	SensingMethod Tag = 41495
+
+	// FileSource indicates the image source (EXIF).
+	FileSource Tag = 41728
+
+	// SceneType indicates the type of scene (EXIF).
+	SceneType Tag = 41729
+
+	// CustomRendered indicates special processing (EXIF).
+	CustomRendered Tag = 41985
+
+	// ExposureMode is the exposure mode set when the image was shot (EXIF).
+	ExposureMode Tag = 41986
+
+	// WhiteBalance is the white balance mode (EXIF).
+	WhiteBalance Tag = 41987
+
+	// DigitalZoomRatio is the digital zoom ratio (EXIF).
+	DigitalZoomRatio Tag = 41988
+
+	// FocalLengthIn35mmFilm is the 35 mm equivalent focal length (EXIF IFD).
+	FocalLengthIn35mmFilm Tag = 41989
+
+	// SceneCaptureType is the type of scene that was shot (EXIF).
+	SceneCaptureType Tag = 41990
+
+	// GainControl is the degree of overall gain adjustment (EXIF).
+	GainControl Tag = 41991
+
+	// Contrast is the contrast processing applied by the camera (EXIF).
+	Contrast Tag = 41992
+
+	// Saturation is the saturation processing applied by the camera (EXIF).
+	Saturation Tag = 41993
+
+	// Sharpness is the sharpness processing applied by the camera (EXIF).
+	Sharpness Tag = 41994
+
+	// DeviceSettingDescription describes the picture-taking conditions (EXIF).
+	DeviceSettingDescription Tag = 41995
+
+	// SubjectDistanceRange is the distance range to the subject (EXIF).
+	SubjectDistanceRange Tag = 41996
+
+	// ImageUniqueID is a unique identifier of the image (EXIF).
+	ImageUniqueID Tag = 42016
+
+	// CameraOwnerName is the name of the camera owner (EXIF).
+	CameraOwnerName Tag = 42032
+
+	// BodySerialNumber is the serial number of the camera body (EXIF IFD).
+	BodySerialNumber Tag = 42033
+
+	// LensSpecification is the minimum and maximum focal length and F number of the lens (EXIF
+	// IFD).
+	LensSpecification Tag = 42034
+
+	// LensMake is the manufacturer of the lens (EXIF IFD).
+	LensMake Tag = 42035
+
+	// LensModel is the model name of the lens (EXIF IFD).
+	LensModel Tag = 42036
+
+	// LensSerialNumber is the serial number of the lens (EXIF IFD).
+	LensSerialNumber Tag = 42037
+
+	// GDALMetadata holds GDAL dataset and band metadata as an XML document.
+	GDALMetadata Tag = 42112
+
+	// GDALNoData holds the GDAL nodata value as an ASCII number.
+	GDALNoData Tag = 42113
+
+	// Gamma is the gamma coefficient of the image (EXIF).
+	Gamma Tag = 42240
+
+	// LercParameters holds the LERC version and additional compression of LERC-compressed data.
+	LercParameters Tag = 50674
+
+	// DNGVersion is the version of the DNG specification the file complies with.
+	DNGVersion Tag = 50706
+
+	// DNGBackwardVersion is the oldest DNG version a reader must support.
+	DNGBackwardVersion Tag = 50707
+
+	// UniqueCameraModel is a unique, non-localized camera model name.
+	UniqueCameraModel Tag = 50708
+
+	// LocalizedCameraModel is a localized camera model name.
+	LocalizedCameraModel Tag = 50709
+
+	// CFAPlaneColor maps CFA plane numbers to colors.
+	CFAPlaneColor Tag = 50710
+
+	// CFALayout is the spatial layout of the CFA.
+	CFALayout Tag = 50711
+
+	// LinearizationTable maps stored values to linear values.
+	LinearizationTable Tag = 50712
+
+	// BlackLevelRepeatDim is the repeat pattern size of BlackLevel.
+	BlackLevelRepeatDim Tag = 50713
+
+	// BlackLevel is the zero light encoding level.
+	BlackLevel Tag = 50714
+
+	// BlackLevelDeltaH is the per-column black level delta.
+	BlackLevelDeltaH Tag = 50715
+
+	// BlackLevelDeltaV is the per-row black level delta.
+	BlackLevelDeltaV Tag = 50716
+
+	// WhiteLevel is the fully saturated encoding level; it defaults to 2**BitsPerSample-1.
+	WhiteLevel Tag = 50717
+
+	// DefaultScale is the default scale factor for each direction.
+	DefaultScale Tag = 50718
+
+	// DefaultCropOrigin is the origin of the final image area.
+	DefaultCropOrigin Tag = 50719
+
+	// DefaultCropSize is the size of the final image area.
+	DefaultCropSize Tag = 50720
+
+	// ColorMatrix1 transforms XYZ values to reference camera native color space under the first
+	// calibration illuminant.
+	ColorMatrix1 Tag = 50721
+
+	// ColorMatrix2 transforms XYZ values to reference camera native color space under the second
+	// calibration illuminant.
+	ColorMatrix2 Tag = 50722
+
+	// CameraCalibration1 transforms reference camera native space values to individual camera
+	// native space under the first calibration illuminant.
+	CameraCalibration1 Tag = 50723
+
+	// CameraCalibration2 transforms reference camera native space values to individual camera
+	// native space under the second calibration illuminant.
+	CameraCalibration2 Tag = 50724
+
+	// ReductionMatrix1 reduces the camera color space dimensions under the first calibration
+	// illuminant.
+	ReductionMatrix1 Tag = 50725
+
+	// ReductionMatrix2 reduces the camera color space dimensions under the second calibration
+	// illuminant.
+	ReductionMatrix2 Tag = 50726
+
+	// AnalogBalance is the gain applied to each color plane before digitization.
+	AnalogBalance Tag = 50727
+
+	// AsShotNeutral is the selected white balance as neutral coordinates.
+	AsShotNeutral Tag = 50728
+
+	// AsShotWhiteXY is the selected white balance as x-y chromaticity coordinates.
+	AsShotWhiteXY Tag = 50729
+
+	// BaselineExposure is the zero point of the exposure in EV units.
+	BaselineExposure Tag = 50730
+
+	// BaselineNoise is the relative noise level of the camera model.
+	BaselineNoise Tag = 50731
+
+	// BaselineSharpness is the relative sharpening of the camera model.
+	BaselineSharpness Tag = 50732
+
+	// BayerGreenSplit is the tracking difference between green pixels of a Bayer CFA.
+	BayerGreenSplit Tag = 50733
+
+	// LinearResponseLimit is the fraction of the encoding range above which the response may be
+	// non-linear.
+	LinearResponseLimit Tag = 50734
+
+	// CameraSerialNumber is the serial number of the camera.
+	CameraSerialNumber Tag = 50735
+
+	// LensInfo holds the minimum and maximum focal lengths and F numbers of the lens.
+	LensInfo Tag = 50736
+
+	// ChromaBlurRadius is the chroma blur radius used for demosaicing.
+	ChromaBlurRadius Tag = 50737
+
+	// AntiAliasStrength is the relative strength of the camera's anti-alias filter.
+	AntiAliasStrength Tag = 50738
+
+	// ShadowScale is used by Adobe Camera Raw to control shadow sensitivity.
+	ShadowScale Tag = 50739
+
+	// DNGPrivateData holds private data for the DNG writer.
+	DNGPrivateData Tag = 50740
+
+	// MakerNoteSafety tells whether MakerNote may be safely preserved on edit.
+	MakerNoteSafety Tag = 50741
+
+	// CalibrationIlluminant1 is the illuminant of the first calibration.
+	CalibrationIlluminant1 Tag = 50778
+
+	// CalibrationIlluminant2 is the illuminant of the second calibration.
+	CalibrationIlluminant2 Tag = 50779
+
+	// BestQualityScale is the extra scale factor for best quality rendering.
+	BestQualityScale Tag = 50780
+
+	// RawDataUniqueID is a unique identifier of the raw image data.
+	RawDataUniqueID Tag = 50781
+
+	// OriginalRawFileName is the file name of the original raw file.
+	OriginalRawFileName Tag = 50827
+
+	// OriginalRawFileData holds the contents of the original raw file.
+	OriginalRawFileData Tag = 50828
+
+	// ActiveArea is the rectangle of the sensor holding valid image data.
+	ActiveArea Tag = 50829
+
+	// MaskedAreas lists the rectangles of masked sensor pixels.
+	MaskedAreas Tag = 50830
+
+	// AsShotICCProfile is the ICC profile for the as-shot rendering.
+	AsShotICCProfile Tag = 50831
+
+	// AsShotPreProfileMatrix is applied before AsShotICCProfile.
+	AsShotPreProfileMatrix Tag = 50832
+
+	// CurrentICCProfile is the ICC profile for the current rendering.
+	CurrentICCProfile Tag = 50833
+
+	// CurrentPreProfileMatrix is applied before CurrentICCProfile.
+	CurrentPreProfileMatrix Tag = 50834
+
+	// ColorimetricReference is the colorimetric reference of the image.
+	ColorimetricReference Tag = 50879
+
+	// CameraCalibrationSignature identifies the camera calibration.
+	CameraCalibrationSignature Tag = 50931
+
+	// ProfileCalibrationSignature identifies the calibration a profile is tied to.
+	ProfileCalibrationSignature Tag = 50932
+
+	// AsShotProfileName names the profile selected when the image was shot.
+	AsShotProfileName Tag = 50934
+
+	// NoiseReductionApplied is the amount of noise reduction applied to the raw data.
+	NoiseReductionApplied Tag = 50935
+
+	// ProfileName is the name of the camera profile.
+	ProfileName Tag = 50936
+
+	// ProfileHueSatMapDims is the size of the hue/saturation/value mapping tables.
+	ProfileHueSatMapDims Tag = 50937
+
+	// ProfileHueSatMapData1 is the hue/saturation/value table for the first calibration
+	// illuminant.
+	ProfileHueSatMapData1 Tag = 50938
+
+	// ProfileHueSatMapData2 is the hue/saturation/value table for the second calibration
+	// illuminant.
+	ProfileHueSatMapData2 Tag = 50939
+
+	// ProfileToneCurve is the default tone curve of the profile.
+	ProfileToneCurve Tag = 50940
+
+	// ProfileEmbedPolicy restricts how the profile may be used.
+	ProfileEmbedPolicy Tag = 50941
+
+	// ProfileCopyright is the copyright of the camera profile.
+	ProfileCopyright Tag = 50942
+
+	// ForwardMatrix1 maps white balanced camera colors to XYZ under the first calibration
+	// illuminant.
+	ForwardMatrix1 Tag = 50964
+
+	// ForwardMatrix2 maps white balanced camera colors to XYZ under the second calibration
+	// illuminant.
+	ForwardMatrix2 Tag = 50965
+
+	// PreviewApplicationName names the application that rendered the preview.
+	PreviewApplicationName Tag = 50966
+
+	// PreviewApplicationVersion is the version of the application that rendered the preview.
+	PreviewApplicationVersion Tag = 50967
+
+	// PreviewSettingsName names the settings used to render the preview.
+	PreviewSettingsName Tag = 50968
+
+	// PreviewSettingsDigest is a digest of the settings used to render the preview.
+	PreviewSettingsDigest Tag = 50969
+
+	// PreviewColorSpace is the color space of the preview.
+	PreviewColorSpace Tag = 50970
+
+	// PreviewDateTime is the date and time the preview was rendered.
+	PreviewDateTime Tag = 50971
+
+	// RawImageDigest is an MD5 digest of the raw image data.
+	RawImageDigest Tag = 50972
+
+	// OriginalRawFileDigest is an MD5 digest of OriginalRawFileData.
+	OriginalRawFileDigest Tag = 50973
+
+	// SubTileBlockSize is the size of interleaved blocks within a tile.
+	SubTileBlockSize Tag = 50974
+
+	// RowInterleaveFactor is the number of interleaved fields.
+	RowInterleaveFactor Tag = 50975
+
+	// ProfileLookTableDims is the size of the profile look table.
+	ProfileLookTableDims Tag = 50981
+
+	// ProfileLookTableData is the profile look table.
+	ProfileLookTableData Tag = 50982
+
+	// OpcodeList1 holds opcodes applied to the raw image as read.
+	OpcodeList1 Tag = 51008
+
+	// OpcodeList2 holds opcodes applied after linearization.
+	OpcodeList2 Tag = 51009
+
+	// OpcodeList3 holds opcodes applied after demosaicing.
+	OpcodeList3 Tag = 51022
+
+	// NoiseProfile describes the noise model of the raw data.
+	NoiseProfile Tag = 51041
+
+	// OriginalDefaultFinalSize is the default final size of the original image.
+	OriginalDefaultFinalSize Tag = 51089
+
+	// OriginalBestQualityFinalSize is the best quality final size of the original image.
+	OriginalBestQualityFinalSize Tag = 51090
+
+	// OriginalDefaultCropSize is the default crop size of the original image.
+	OriginalDefaultCropSize Tag = 51091
+
+	// ProfileHueSatMapEncoding is the encoding of the hue/saturation/value tables.
+	ProfileHueSatMapEncoding Tag = 51107
+
+	// ProfileLookTableEncoding is the encoding of the profile look table.
+	ProfileLookTableEncoding Tag = 51108
+
+	// BaselineExposureOffset is added to BaselineExposure.
+	BaselineExposureOffset Tag = 51109
+
+	// DefaultBlackRender is the preferred black rendering.
+	DefaultBlackRender Tag = 51110
+
+	// NewRawImageDigest is an MD5 digest of the raw image data.
+	NewRawImageDigest Tag = 51111
+
+	// RawToPreviewGain is the gain between the raw and preview images.
+	RawToPreviewGain Tag = 51112
+
+	// DefaultUserCrop is the default user crop rectangle as fractions of the default crop.
+	DefaultUserCrop Tag = 51125
 )
 
 // String returns a human-readable name for the TIFF tag.
 // If the tag is unknown, it returns a formatted numeric identifier.
 func (t Tag) String() string {
-	switch t {
-	case ImageWidth:
-		return "ImageWidth"
-	case ImageLength:
-		return "ImageLength"
-	case BitsPerSample:
-		return "BitsPerSample"
-	case Compression:
-		return "Compression"
-	case PhotometricInterpretation:
-		return "PhotometricInterpretation"
-	case StripOffsets:
-		return "StripOffsets"
-	case SamplesPerPixel:
-		return "SamplesPerPixel"
-	case RowsPerStrip:
-		return "RowsPerStrip"
-	case StripByteCounts:
-		return "StripByteCounts"
-	case PlanarConfiguration:
-		return "PlanarConfiguration"
-	case TileWidth:
-		return "TileWidth"
-	case TileLength:
-		return "TileLength"
-	case TileOffsets:
-		return "TileOffsets"
-	case TileByteCounts:
-		return "TileByteCounts"
-	default:
-		return fmt.Sprintf("Tag(%d)", t)
+	if info, ok := Lookup(t); ok {
+		return info.Name
 	}
+	return fmt.Sprintf("Tag(%d)", t)
 }
@@ -0,0 +1,122 @@
+// Package xmp provides a lightweight view of XMP packets, as embedded in TIFF
+// files through the XMP tag (700).
+//
+// The packet is kept as raw XML for full processing by the caller; in
+// addition, simple properties (attributes and text values of rdf:Description,
+// including the items of rdf:Alt, rdf:Bag and rdf:Seq arrays) are collected
+// by their prefixed name, such as "dc:title" or "xmp:CreatorTool".
+//
+// Reference: https://www.adobe.com/devnet/xmp.html
+package xmp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+// ErrNotFound is returned by FromDirectory when the image has no XMP packet.
+var ErrNotFound = errors.New("xmp: no embedded packet")
+
+// Packet is an XMP packet with its simple properties.
+type Packet struct {
+	// Raw holds the XML of the packet as embedded in the file.
+	Raw []byte
+
+	// Properties maps prefixed property names to their values in document
+	// order. Array properties have one value per item.
+	Properties map[string][]string
+}
+
+// Get returns the first value of the property name, e.g. Get("dc:creator").
+func (p *Packet) Get(name string) (string, bool) {
+	vs := p.Properties[name]
+	if len(vs) == 0 {
+		return "", false
+	}
+	return vs[0], true
+}
+
+// FromDirectory parses the XMP packet embedded in the image described by d.
+func FromDirectory(d *ifd.Directory) (*Packet, error) {
+	raw, ok := d.Bytes(tifftag.XMP)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return Parse(raw)
+}
+
+// Parse collects the simple properties of an XMP packet.
+func Parse(raw []byte) (*Packet, error) {
+	p := &Packet{Raw: raw, Properties: map[string][]string{}}
+
+	dec := xml.NewDecoder(bytes.NewReader(bytes.TrimRight(raw, "\x00 ")))
+	dec.Strict = false
+
+	var stack []string // prefixed names of the open elements
+	property := ""     // property whose value is being read
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("xmp: invalid packet: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := prefixed(t.Name)
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			switch {
+			case name == "rdf:Description":
+				for _, a := range t.Attr {
+					if a.Name.Space == "xmlns" || a.Name.Space == "rdf" || a.Name.Space == "" {
+						continue
+					}
+					key := prefixed(a.Name)
+					p.Properties[key] = append(p.Properties[key], a.Value)
+				}
+			case parent == "rdf:Description" && t.Name.Space != "rdf":
+				property = name
+			}
+			stack = append(stack, name)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				if stack[len(stack)-1] == property {
+					property = ""
+				}
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if property == "" || len(stack) == 0 {
+				continue
+			}
+			top := stack[len(stack)-1]
+			if top != property && top != "rdf:li" {
+				continue
+			}
+			if v := strings.TrimSpace(string(t)); v != "" {
+				p.Properties[property] = append(p.Properties[property], v)
+			}
+		}
+	}
+	return p, nil
+}
+
+// prefixed returns the name as "prefix:local", or just the local name if unprefixed.
+func prefixed(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
@@ -0,0 +1,77 @@
+package xmp
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/echoflaresat/tiff/fieldtype"
+	"github.com/echoflaresat/tiff/ifd"
+	"github.com/echoflaresat/tiff/internal/tifftest"
+	"github.com/echoflaresat/tiff/tifftag"
+)
+
+const packet = `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/"
+    xmlns:dc="http://purl.org/dc/elements/1.1/" xmp:CreatorTool="darktable">
+   <dc:creator><rdf:Seq><rdf:li>Jane Doe</rdf:li></rdf:Seq></dc:creator>
+   <dc:subject><rdf:Bag><rdf:li>sky</rdf:li><rdf:li>sea</rdf:li></rdf:Bag></dc:subject>
+   <xmp:Rating>4</xmp:Rating>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>` + "\x00\x00"
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(packet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string][]string{
+		"xmp:CreatorTool": {"darktable"},
+		"dc:creator":      {"Jane Doe"},
+		"dc:subject":      {"sky", "sea"},
+		"xmp:Rating":      {"4"},
+	} {
+		if got := p.Properties[name]; !slices.Equal(got, want) {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, ok := p.Properties["rdf:about"]; ok {
+		t.Errorf("rdf attributes recorded as properties")
+	}
+	if v, ok := p.Get("dc:subject"); !ok || v != "sky" {
+		t.Errorf("Get(dc:subject) = %q, %v", v, ok)
+	}
+	if _, ok := p.Get("dc:title"); ok {
+		t.Errorf("Get(dc:title) found")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte("<x:xmpmeta><rdf:RDF>&")); err == nil {
+		t.Errorf("Parse() accepted malformed XML")
+	}
+}
+
+func TestFromDirectory(t *testing.T) {
+	entry := tifftest.Entry{Tag: tifftag.XMP, Type: fieldtype.Byte, Raw: []byte(packet)}
+	dirs, err := ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: []tifftest.Entry{entry}})), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := FromDirectory(dirs[0]); err != nil || !bytes.Equal(p.Raw, []byte(packet)) {
+		t.Errorf("FromDirectory() = %v", err)
+	}
+
+	dirs, err = ifd.ReadAll(bytes.NewReader(tifftest.Build(tifftest.IFD{Entries: tifftest.Gray(1, 1)})), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromDirectory(dirs[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FromDirectory() = %v, want ErrNotFound", err)
+	}
+}